@@ -31,8 +31,7 @@ func repaireLas(fileName string) {
 	fmt.Printf(" read\n")
 	// примеры проверки прочитанного las файла
 	if las.IsWraped() {
-		fmt.Printf("wrapped\n")
-		return
+		fmt.Printf("wrapped\n") // wrapped file saved unwrapped, one line per depth step
 	}
 	if n == 0 {
 		fmt.Printf("data not exist\n")
@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -23,11 +24,10 @@ import (
 )
 
 // Las - class to store las file
-// input code page autodetect
+// input code page autodetect, or specified by SetInputCodepage()
 // at read file the code page is converted to UTF
 // at save file code page converted to specifyed in Las.oCodepage
 //TODO add pointer to cfg
-//TODO при создании объекта las есть возможность указать кодировку записи, нужна возможность указать явно кодировку чтения
 type Las struct {
 	rows            []string           // buffer for read source file, only converted to UTF-8 no any othe change
 	FileName        string             // file name from load
@@ -38,15 +38,48 @@ type Las struct {
 	LogDic          *map[string]string // external dictionary of standart log name - mnemonics
 	VocDic          *map[string]string // external vocabulary dictionary of log mnemonic
 	Warnings        TLasWarnings       // slice of warnings occure on read or write
+	iCodepage       cpd.IDCodePage     // codepage of input, detected on load or specified by SetInputCodepage()
+	iCodepageSet    bool               // true if input codepage specified by SetInputCodepage(), autodetect not used
 	oCodepage       cpd.IDCodePage     // codepage to save, default xlib.CpWindows1251. to special value, specify at make: NewLas(cp...)
+	oWrapWidth      int                // max line width to save wrapped file, 0 - save one line per depth step (default), specify by SetWrapWidth()
+	oVersion        float64            // version of las file to save: 1.2, 2.0 (default) or 3.0, specify by SetSaveVersion()
+	oDLM            string             // delimiter of data section to save las 3.0: SPACE, COMMA, TAB
 	currentLine     int                // index of current line in readed file
+	dataStart       int                // index in rows of first line of main data section
 	maxWarningCount int                // default maximum warning count
 	stdNull         float64            // default null value
+	row             LasRow             // buffer for current row of data section
+	lastDept        [2]float64         // two previous depths, for check monotony
+	numDept         int                // number of depths read, for check monotony
 	VerSec,
 	WelSec,
 	CurSec,
 	ParSec,
 	OthSec HeaderSection
+	Groups       []*LasGroup        // data groups of las 3.0: ~Core_*, ~Tops_* and other, main log group stored in Logs, CurSec, ParSec
+	WellInfo     WellInfo           // information about well from section ~W, filled on load, changes written on save
+	wellInfo     WellInfo           // WellInfo as it was read, to find changed fields
+	lossless     bool               // lossless mode of save, specify by SetLossless()
+	header       map[int]headerItem // parameters and curves as they was read, key - number of line
+	pointLines   []lineRange        // source lines of each record of data section
+	recordLines  []lineRange        // source lines of records in order of file if points reordered by repairs, nil - same as pointLines
+	recFirst     int                // first line of current record of data section
+	eol          string             // line ending of source: "\n" or "\r\n"
+	lastEOL      bool               // last line of source ended by line ending
+	checker      Checker            // checks of header on load, specify by SetChecker(), nil - NewStdChecker()
+	columnErrs   int                // number of lines of data section with wrong number of columns
+	columnLine   int                // first line of data section with wrong number of columns
+	Repairs      RepairLog          // log of repairs applied on load, what and where changed
+	repairPolicy RepairPolicy       // repairs applied on load, specify by SetRepairPolicy(), nil - NewStdRepairPolicy()
+	strict       bool               // strict mode of load, specify by SetStrict()
+	strictErr    *LoadError         // first violation of standard in strict mode
+	secLines     map[rune]int       // lines of titles of sections, key: first letter of section
+	stat         *dataStat          // summary of data section for checks of data
+	streamHead   int                // lines of data section read before streaming, specify by SetStreamHead(), 0 - streamHeadSize
+	recLines     []dataLine         // lines of current record of data section, for position of violation in strict mode
+	streaming    bool               // data section read by LoadStream(), repairs of data only counted
+	missingVals  int                // on streaming read: number of missing values of data section set to NULL
+	missingLine  int                // on streaming read: first line with missing values
 }
 
 var (
@@ -60,6 +93,9 @@ var (
 	MaxWarningCount int = 20
 )
 
+// cpDetectSize - number of bytes from begin of input used to detect codepage
+const cpDetectSize = 4096
+
 //method for get values from header containers ////////////////
 
 func (las *Las) parFloat(sec HeaderSection, name string, defValue float64) float64 {
@@ -86,18 +122,21 @@ func (las *Las) NULL() float64 {
 
 // STOP - return depth stop value of las file as float64
 // if parameter STOP in las file not exist, then return StdNull (by default -999.25)
+// for date-time index return seconds from 1970-01-01 UTC
 func (las *Las) STOP() float64 {
-	return las.parFloat(las.WelSec, "STOP", StdNull)
+	return las.parIndex(las.WelSec, "STOP", StdNull)
 }
 
 // STRT - return depth start value of las file as float64
 // if parameter NULL in las file not exist, then return StdNull (by default -999.25)
+// for date-time index return seconds from 1970-01-01 UTC
 func (las *Las) STRT() float64 {
-	return las.parFloat(las.WelSec, "STRT", StdNull)
+	return las.parIndex(las.WelSec, "STRT", StdNull)
 }
 
 // STEP - return depth step value of las file as float64
 // if parameter not exist, then return StdNull (by default -999.25)
+// for date-time index step in seconds
 func (las *Las) STEP() float64 {
 	return las.parFloat(las.WelSec, "STEP", StdNull)
 }
@@ -114,6 +153,12 @@ func (las *Las) WRAP() string {
 	return las.parStr(las.VerSec, "WRAP", "NO")
 }
 
+// DLM - return delimiter of data section, parameter of las 3.0
+// if parameter not exist, then return "SPACE"
+func (las *Las) DLM() string {
+	return strings.ToUpper(las.parStr(las.VerSec, "DLM", "SPACE"))
+}
+
 // WELL - return well name
 // if parameter WELL in las file not exist, then return "--"
 func (las *Las) WELL() string {
@@ -132,8 +177,11 @@ func NewLas(outputCP ...cpd.IDCodePage) *Las {
 	las.CurSec = NewCurSection()
 	las.ParSec = NewParSection()
 	las.OthSec = NewOthSection()
+	las.Groups = make([]*LasGroup, 0)
 	las.maxWarningCount = MaxWarningCount
 	las.stdNull = StdNull
+	las.oVersion = 2.0
+	las.oDLM = "SPACE"
 	if len(outputCP) > 0 {
 		las.oCodepage = outputCP[0]
 	} else {
@@ -151,6 +199,97 @@ func (las *Las) IsWraped() bool {
 	return strings.Contains(strings.ToUpper(las.WRAP()), "Y")
 }
 
+// SetInputCodepage - set codepage of input, on load autodetect not used
+func (las *Las) SetInputCodepage(cp cpd.IDCodePage) {
+	las.iCodepage = cp
+	las.iCodepageSet = true
+}
+
+// InputCodepage - return codepage of input
+// after load return detected codepage or specified by SetInputCodepage()
+func (las *Las) InputCodepage() cpd.IDCodePage {
+	return las.iCodepage
+}
+
+// newReader - create reader decoding input to UTF-8
+// input codepage autodetect by first cpDetectSize bytes, if it not specified by SetInputCodepage()
+func (las *Las) newReader(reader io.Reader) (io.Reader, error) {
+	if !las.iCodepageSet {
+		br := bufio.NewReaderSize(reader, cpDetectSize)
+		buf, _ := br.Peek(cpDetectSize) // on short input return all bytes and error, it not matter
+		cp, err := cpd.CodepageDetect(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		las.iCodepage = cp
+		reader = br
+	}
+	return cpd.NewReader(reader, las.iCodepage.String())
+}
+
+// resetLoad - clear result of previous load: header, curves, warnings, repairs, settings of las kept
+func (las *Las) resetLoad() {
+	las.rows = las.rows[:0]
+	las.Logs = make([]LasCurve, 0)
+	las.Warnings = nil
+	las.VerSec = NewVerSection()
+	las.WelSec = NewWelSection()
+	las.CurSec = NewCurSection()
+	las.ParSec = NewParSection()
+	las.OthSec = NewOthSection()
+	las.Groups = make([]*LasGroup, 0)
+	las.WellInfo, las.wellInfo = WellInfo{}, WellInfo{}
+	las.header, las.pointLines, las.recordLines, las.recLines = nil, nil, nil, nil
+	las.columnErrs, las.columnLine = 0, 0
+	las.streaming, las.missingVals, las.missingLine = false, 0, 0
+	las.Repairs, las.stat = nil, nil
+	las.currentLine, las.dataStart, las.recFirst = 0, 0, 0
+	las.lastDept, las.numDept = [2]float64{}, 0
+	las.eol, las.lastEOL = "", false
+	las.resetStrict()
+}
+
+// SetChecker - set checks of header performed on load, warnings of failed checks added to Warnings
+// nil - standard checks of NewStdChecker()
+func (las *Las) SetChecker(checker Checker) {
+	las.checker = checker
+}
+
+// wrapValueWidth - width of one value in wrapped data section, format " %10.4f"
+const wrapValueWidth = 11
+
+// SetWrapWidth - set max width of data line on save
+// width > 0 - file saved with WRAP = YES, depth alone on first line of record, curve values wrapped to lines not longer than width
+// width less than width of one value (11) increased to it, line contains at least one value
+// width <= 0 - file saved with WRAP = NO, one line per depth step
+func (las *Las) SetWrapWidth(width int) {
+	switch {
+	case width < 0:
+		width = 0
+	case (width > 0) && (width < wrapValueWidth):
+		width = wrapValueWidth
+	}
+	las.oWrapWidth = width
+}
+
+// SetSaveVersion - set version of las file on save: 1.2, 2.0 (default) or 3.0
+// dlm - delimiter of data section for las 3.0: SPACE (default), COMMA, TAB, ignored for 2.0
+func (las *Las) SetSaveVersion(vers float64, dlm ...string) error {
+	if (vers != 1.2) && (vers != 2.0) && (vers != 3.0) {
+		return fmt.Errorf("las version %3.1f not support on save, expected 1.2, 2.0 or 3.0", vers)
+	}
+	d := "SPACE"
+	if len(dlm) > 0 {
+		d = strings.ToUpper(strings.TrimSpace(dlm[0]))
+	}
+	if (d != "SPACE") && (d != "COMMA") && (d != "TAB") {
+		return fmt.Errorf("delimiter '%s' not support, expected SPACE, COMMA or TAB", d)
+	}
+	las.oVersion = vers
+	las.oDLM = d
+	return nil
+}
+
 // GetRows - get internal field 'rows'
 func (las *Las) GetRows() []string {
 	return las.rows
@@ -164,48 +303,107 @@ func (las *Las) ReadRows() int {
 	return len(las.rows)
 }
 
+// scanError - return error of reading source, nil if source read to end
+func (las *Las) scanError() error {
+	if err := las.scanner.Err(); err != nil {
+		return &LoadError{File: las.FileName, Line: len(las.rows) + 1, Err: ErrRead, Cause: err}
+	}
+	return nil
+}
+
 // Load - load las from reader
 // you can make reader from string or other containers and send as input parameters
+// on fatal error return *LoadError, kind of error checked by errors.Is(err, ErrNoCurves) ...
 func (las *Las) Load(reader io.Reader) (int, error) {
 	var err error
 	if reader == nil {
-		return 0, errors.New("Load received nil reader")
+		return 0, &LoadError{File: las.FileName, Err: ErrNilReader}
 	}
+	las.resetLoad()
 	//create Reader, this reader decodes to UTF-8 from reader
-	las.Reader, err = cpd.NewReader(reader)
+	las.Reader, err = las.newReader(reader)
 	if err != nil {
-		return 0, err //FATAL error - file cannot be decoded to UTF-8
+		return 0, &LoadError{File: las.FileName, Err: ErrDecode, Cause: err} //FATAL error - file cannot be decoded to UTF-8
 	}
 	// prepare file to read
-	las.scanner = bufio.NewScanner(las.Reader)
+	las.scanner = las.newScanner()
 	las.ReadRows()
+	if err = las.scanError(); err != nil {
+		return 0, err
+	}
 	m, _ := las.LoadHeader()
-	stdChecker := NewStdChecker()
+	las.storeHeader()
+	if err = las.strictHeader(); err != nil {
+		return 0, err
+	}
+	if err = las.checkHeader(); err != nil {
+		return 0, err
+	}
+	las.LoadDataSec(m)
+	if las.strictErr != nil {
+		return las.NumPoints(), las.strictErr
+	}
+	las.repairData()
+	las.stat = nil // checks of data use loaded curves
+	return las.NumPoints(), las.checkData()
+}
+
+// getChecker - return checker specified by SetChecker() or standard checker
+func (las *Las) getChecker() Checker {
+	if las.checker == nil {
+		return NewStdChecker()
+	}
+	return las.checker
+}
+
+// checkData - check loaded data, return error if check of data give fatal error
+func (las *Las) checkData() error {
+	r := las.getChecker().CheckData(las)
+	las.storeHeaderWarning(r)
+	if c, ok := r.fatal(); ok {
+		return las.loadError(c.Err, c.Warning.Section, nil)
+	}
+	return nil
+}
+
+// checkHeader - check parameters of header by standard checker and repair it
+// return error if las can not be read
+func (las *Las) checkHeader() error {
 	// check for FATAL errors
-	r := stdChecker.check(las)
+	r := las.getChecker().Check(las)
 	las.storeHeaderWarning(r)
-	if err = r.fatal(); err != nil {
-		return 0, err
+	if c, ok := r.fatal(); ok {
+		return las.loadError(c.Err, c.Warning.Section, nil)
 	}
-	if r.nullWrong() {
+	p := las.getRepairPolicy()
+	if r.nullWrong() && p[RepairNull] {
+		old, _ := las.WelSec.Get("NULL")
 		las.SetNull(las.stdNull)
+		las.repairParam(RepairNull, "NULL", old, "NULL = 0 replaced by standard null value")
 	}
-	if r.strtWrong() {
+	if r.strtWrong() && p[RepairStrt] {
+		old, _ := las.WelSec.Get("STRT")
 		h := las.GetStrtFromData() // return las.Null if cannot find strt in the data section.
 		if h == las.NULL() {
-			las.addWarning(TWarning{directOnRead, lasSecWellInfo, -1, fmt.Sprint("__WRN__ STRT parameter on data is wrong setting to 0")})
+			las.addWarning(newWarning(WarnStrtWrong, lasSecWellInfo, -1, "STRT parameter on data is wrong setting to 0"))
 			las.setStrt(0)
 		}
 		las.setStrt(h)
+		las.repairParam(RepairStrt, "STRT", old, "STRT taken from data")
 	}
-	if r.stepWrong() {
+	if r.stepWrong() && p[RepairStep] {
+		old, _ := las.WelSec.Get("STEP")
 		h := las.GetStepFromData() // return las.Null if cannot calculate step from data
 		if h == las.NULL() {
-			las.addWarning(TWarning{directOnRead, lasSecWellInfo, las.currentLine, fmt.Sprint("__WRN__ STEP parameter on data is wrong")})
+			las.addWarning(newWarning(WarnStepWrong, lasSecWellInfo, las.currentLine, "STEP parameter on data is wrong"))
+		}
+		if h == 0 {
+			las.addWarning(newWarning(WarnIndexIrregular, lasSecWellInfo, las.currentLine, "index is irregular, STEP set to 0"))
 		}
 		las.setStep(h)
+		las.repairParam(RepairStep, "STEP", old, "STEP taken from data")
 	}
-	return las.LoadDataSec(m)
+	return nil
 }
 
 // Open - read las file
@@ -213,7 +411,7 @@ func (las *Las) Open(fileName string) (int, error) {
 	var err error
 	las.File, err = os.Open(fileName)
 	if err != nil {
-		return 0, err //FATAL error - file not exist
+		return 0, &LoadError{File: fileName, Err: ErrRead, Cause: err} //FATAL error - file not exist
 	}
 	defer las.File.Close()
 	las.FileName = fileName
@@ -229,22 +427,45 @@ func (las *Las) Open(fileName string) (int, error) {
 5. читаем одну строку (это один параметер из известной нам секции)
 */
 func (las *Las) LoadHeader() (int, error) {
+	m, err := las.loadHeader(0)
+	las.dataStart = m
+	las.readWellInfo()
+	return m, err
+}
+
+// loadHeader - load sections from line start of rows
+// for streaming read of las 3.0 header continues after main data section
+func (las *Las) loadHeader(start int) (int, error) {
 	var (
 		sec HeaderSection
+		grp *LasGroup // current data group of las 3.0, nil for main log group (~Curve, ~Parameter, ~A)
 	)
-	//secNum := 0
-	las.currentLine = 0
-	for _, s := range las.rows {
-		s = strings.TrimSpace(s)
+	m := -1 // line of main data section, for las 3.0 header continues after data section
+	las.currentLine = start
+	for (las.currentLine < len(las.rows)) && (las.strictErr == nil) {
+		s := strings.TrimSpace(las.rows[las.currentLine])
 		las.currentLine++
 		if isIgnoredLine(s) {
 			continue
 		}
 		if s[0] == '~' { //start new section
-			if las.isDataSection(rune(s[1])) {
+			las.storeSectionLine(s)
+			if las.VERS() >= 3.0 {
+				sec, grp = las.section30(s, &m)
+				continue
+			}
+			r := sectionLetter(s)
+			if r == 0 {
+				las.addWarning(newWarning(WarnSectionName, lasSecIgnore, las.currentLine, "section title '%s' without name, read as section ~O", s))
+			}
+			if las.isDataSection(r) {
 				break // reached the data section, stop load header
 			}
-			sec = las.section(rune(s[1]))
+			sec = las.section(r)
+			continue
+		}
+		if sec.parse == nil { // line before first section
+			las.addWarning(newWarning(WarnOutsideSection, lasSecIgnore, las.currentLine, "line '%s' before first section ignored", s))
 			continue
 		}
 		//not comment, not empty and not new section => parameter, read it
@@ -252,18 +473,37 @@ func (las *Las) LoadHeader() (int, error) {
 		if !w.Empty() {
 			las.addWarning(w)
 		}
-		p.Name = sec.uniqueName(p.Name) //TODO if a duplicate of any parameter is detected, a warning should be generated
-		sec.params[p.Name] = p
+		if name := sec.add(p); (len(name) > 0) && (sec.name != 'C') { // renamed curves reported on add to Logs
+			las.addWarning(newWarning(WarnDuplicateParam, sec.warningSection(), las.currentLine, "duplicate parameter '%s' renamed to '%s'", p.Name, name))
+		}
+		if grp != nil {
+			if sec.name == 'C' {
+				grp.addCurve(s, las)
+			}
+			continue
+		}
 		if sec.name == 'C' { //for ~Curve section need additional actions
 			err := las.readCurveParam(s) //make new curve from "s" and store to container "Logs"
 			if err != nil {
-				las.addWarning(TWarning{directOnRead, 3, las.currentLine, fmt.Sprintf("param: '%s' error: %v", s, err)})
+				las.addWarning(newWarning(WarnCurveParam, lasSecCurInfo, las.currentLine, "param: '%s' error: %v", s, err))
 			}
 		}
 	}
+	if m >= 0 {
+		return m, nil
+	}
 	return las.currentLine, nil
 }
 
+// sectionLetter - return first letter of name of section, s - title of section: "~W", "~Well information"
+// return 0 if title contains only '~'
+func sectionLetter(s string) rune {
+	if len(s) < 2 {
+		return 0
+	}
+	return rune(s[1])
+}
+
 // isDataSection - return true if data section reached
 func (las *Las) isDataSection(r rune) bool {
 	return (r == 0x41) || (r == 0x61)
@@ -293,90 +533,296 @@ func (las *Las) section(r rune) HeaderSection {
 
 // saveHeaderWarning - забирает и сохраняет варнинги от всех проверок
 func (las *Las) storeHeaderWarning(chkResults CheckResults) {
-	for _, v := range chkResults {
-		las.addWarning(v.warning)
+	names := make([]string, 0, len(chkResults))
+	for key := range chkResults {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		las.addWarning(chkResults[key].Warning)
 	}
 }
 
 //Разбор одной строки с мнемоникой каротажа
 //Разбираем а потом сохраняем в slice
 //Каждый каротаж характеризуется тремя именами
-//Name     - имя каротажа, повторятся не может, если есть повторение, то Name строится добавлением к IName индекса
+//Name     - имя каротажа, повторятся не может, при повторении первый каротаж сохраняет имя, следующие получают IName:2, IName:3 ...
 //IName    - имя каротажа в исходном файле, может повторятся
 //Mnemonic - мнемоника, берётся из словаря, если в словаре не найдено, то ""
 func (las *Las) readCurveParam(s string) error {
 	l := NewLasCurve(s, las)
+	if las.VERS() >= 3.0 {
+		l.parseFormat()
+	}
+	las.warnRenamed(l)
 	las.Logs = append(las.Logs, l) //добавление в хранилище кривой каротажа с колонкой глубин
 	return nil
 }
 
-// тестирование на монотонность трёх последних точек глубин внесённых в контейнер
-func (las *Las) deptMonotony() CheckRes {
-	if len(las.Logs) == 0 || len(las.Logs[0].D) <= 3 {
-		return CheckRes{"DPTM", TWarning{directOnRead, lasSecWellInfo, las.currentLine, ""}, nil, true}
+// warnRenamed - add warning if name of curve duplicated and curve renamed
+func (las *Las) warnRenamed(c LasCurve) {
+	if c.Name != c.IName {
+		las.addWarning(newWarning(WarnDuplicateCurve, lasSecCurInfo, las.currentLine, "duplicate curve '%s' renamed to '%s'", c.IName, c.Name))
 	}
-	i := len(las.Logs[0].D) - 1 // индекс последней добавленной в контейнер глубины index of last element in container
-	res := (las.Logs[0].D[i] - las.Logs[0].D[i-1]) == (las.Logs[0].D[i-1] - las.Logs[0].D[i-2])
-	return CheckRes{"DPTM", TWarning{directOnRead, lasSecWellInfo, las.currentLine, "depth not monotony"}, nil, res}
+}
+
+// тестирование на монотонность трёх последних точек глубин
+// dept - new depth, previous two depths stored in las.lastDept
+func (las *Las) deptMonotony(dept float64) CheckRes {
+	res := (las.numDept <= 2) || ((dept - las.lastDept[1]) == (las.lastDept[1] - las.lastDept[0]))
+	las.lastDept[0], las.lastDept[1] = las.lastDept[1], dept
+	las.numDept++
+	return CheckRes{"DPTM", newWarning(WarnDepthMonotony, lasSecData, las.currentLine, "depth not monotony"), nil, res}
 }
 
 // LoadDataSec - read data section from rows
+// for wrapped file (WRAP = YES) lines are collected into one depth record, record size is number of curves from section ~C
 func (las *Las) LoadDataSec(m int) (int, error) {
-	var (
-		v    float64
-		err  error
-		dept float64
-	)
-	n := len(las.Logs)                                       // количество каротажей, столько колонок данных ожидаем
-	dataRows := las.rows[m:]                                 // reslice to lines with data only
-	nullAsStr := strconv.FormatFloat(las.NULL(), 'f', 5, 64) // Null as string
+	i := m
+	next := func() (string, bool) {
+		if i >= len(las.rows) {
+			return "", false
+		}
+		i++
+		return las.rows[i-1], true
+	}
 	las.currentLine = m - 1
-	for _, line := range dataRows {
+	las.loadData(next, las.storeDataRow)
+	return las.NumPoints(), nil
+}
+
+// loadData - read lines of data section
+// next - return next line, false at end of input
+// store - receive each record of data section
+// for wrapped file (WRAP = YES) lines are collected into one record, record size is number of curves from section ~C
+// returns line which begins next section of las 3.0, or "" if input finished
+func (las *Las) loadData(next func() (string, bool), store func(fields []string)) string {
+	n := len(las.Logs)
+	wrap := las.IsWraped()
+	dlm := las.DLM()
+	las.numDept = 0
+	record := make([]string, 0, n) // wrapped record, collected from several lines
+	prevLen := 0                   // number of values in record before its last line
+	lastSingle := false            // last line of record contains one value
+	complete := false              // record complete, stored on next line: its last value may be depth of next record
+	sectionLine := ""
+	for las.strictErr == nil {
+		raw, ok := next()
+		if !ok {
+			break
+		}
 		las.currentLine++
-		line = strings.TrimSpace(line) // reslice
+		line := strings.TrimSpace(raw) // reslice
 		if isIgnoredLine(line) {
 			continue
 		}
-		fields := strings.Fields(line) //separators: tab and space
-		//line must have n columns
-		if len(fields) == 0 { // empty line: warning and ignore
-			las.addWarning(TWarning{directOnRead, lasSecData, las.currentLine, "wow this happened, the line is empty, ignore"})
+		if line[0] == '~' {
+			sectionLine = line // next section of las 3.0, data section finished
+			break
+		}
+		fields := splitDataLine(line, dlm)
+		if !wrap {
+			las.recFirst = las.currentLine
+			las.recLines = append(las.recLines[:0], dataLine{las.currentLine, raw})
+			store(fields)
 			continue
 		}
-		if len(fields) != n {
-			las.addWarning(TWarning{directOnRead, lasSecData, las.currentLine, fmt.Sprintf("line contains %d columns, expected: %d", len(fields), n)})
+		if lastSingle && (prevLen > 0) && (len(fields) > 1) && (len(record)+len(fields) > n) {
+			// values of line not fit to record: single value of previous line is depth of new record, record not complete
+			last := len(las.recLines) - 1
+			depth, depthLine := record[prevLen], las.recLines[last]
+			las.recLines = las.recLines[:last]
+			las.storeWrapRecord(record[:prevLen], store, "")
+			record = append(record[:0], depth)
+			las.recFirst = depthLine.n
+			las.recLines = append(las.recLines[:0], depthLine)
+			complete = false
+		} else if complete {
+			las.storeWrapRecord(record, store, "")
+			record = record[:0]
+			complete = false
 		}
-		// we will analyze the first column separately to check for monotony, and if occure error on parse first column then all line ignore
-		dept, err = strconv.ParseFloat(fields[0], 64)
-		if err != nil {
-			las.addWarning(TWarning{directOnRead, lasSecData, las.currentLine, fmt.Sprintf("dept:'%s' not numeric, line ignore", fields[0])})
-			continue
+		if las.strictErr != nil {
+			break
 		}
-		las.Logs[0].D = append(las.Logs[0].D, dept)
-		las.Logs[0].V = append(las.Logs[0].V, dept) //TODO надо подумать про колонку значений для кривой DEPT
-		// проверка монотонности шага
-		if cr := las.deptMonotony(); !cr.res {
-			las.addWarning(cr.warning)
+		if len(record) == 0 {
+			las.recFirst = las.currentLine
+			las.recLines = las.recLines[:0]
 		}
+		las.recLines = append(las.recLines, dataLine{las.currentLine, raw})
+		prevLen, lastSingle = len(record), len(fields) == 1
+		record = append(record, fields...)
+		if len(record) < n {
+			continue // record not complete, next line continues it
+		}
+		if len(record) > n {
+			las.addColumnLine()
+			las.dataViolation(n, "wrapped record contains %d values, expected: %d", len(record), n)
+			if las.strictErr != nil {
+				break
+			}
+			las.addWarning(newWarning(WarnWrapRecordLength, lasSecData, las.currentLine, "wrapped record contains %d values, expected: %d, extra values ignored", len(record), n))
+			record = record[:n]
+		}
+		complete = true
+	}
+	if wrap && (len(record) > 0) && (las.strictErr == nil) {
+		las.storeWrapRecord(record, store, "last ") // data section ended, last record may be not complete
+	}
+	return sectionLine
+}
 
-		for j := 1; j < n; j++ { // цикл по каротажам
-			s := ""
-			if j >= len(fields) {
-				s = nullAsStr // columns count in current line less than curves count, fill as null value
-				las.addWarning(TWarning{directOnRead, lasSecData, las.currentLine, fmt.Sprintf("for column %d data not present, value set to NULL", j+1)})
-			} else {
-				s = fields[j]
+// storeWrapRecord - store wrapped record collected from lines recLines, warnings refer to last line of record
+// record with less values than curves stored by storeShortRecord(), prefix - begin of its warning message
+func (las *Las) storeWrapRecord(record []string, store func(fields []string), prefix string) {
+	cur := las.currentLine
+	las.currentLine = las.recLines[len(las.recLines)-1].n
+	if len(record) < len(las.Logs) {
+		las.storeShortRecord(record, store, prefix)
+	} else {
+		store(record)
+	}
+	las.currentLine = cur
+}
+
+// storeShortRecord - store wrapped record contains less values than curves, missing values set to NULL
+// if repair MISSING disabled record ignored, prefix - begin of warning message: "" or "last "
+func (las *Las) storeShortRecord(record []string, store func(fields []string), prefix string) {
+	n := len(las.Logs)
+	las.addColumnLine()
+	las.dataViolation(len(record), "%swrapped record contains %d values, expected: %d", prefix, len(record), n)
+	if las.strictErr != nil {
+		return
+	}
+	if !las.repairEnabled(RepairMissing) {
+		las.addWarning(newWarning(WarnWrapRecordLength, lasSecData, las.currentLine, "%swrapped record contains %d values, expected: %d, record ignored", prefix, len(record), n))
+		return
+	}
+	las.addWarning(newWarning(WarnWrapRecordLength, lasSecData, las.currentLine, "%swrapped record contains %d values, expected: %d, missing values set to NULL", prefix, len(record), n))
+	las.addMissingRepair(RepairRecord{RepairMissing, las.currentLine + 1, "", "", "", fmt.Sprintf("%d missing values of %swrapped record set to NULL", n-len(record), prefix)}, n-len(record))
+	nullAsStr := strconv.FormatFloat(las.NULL(), 'f', 5, 64)
+	for len(record) < n {
+		record = append(record, nullAsStr)
+	}
+	store(record)
+}
+
+// addColumnLine - count line of data section with wrong number of columns, only first line kept
+func (las *Las) addColumnLine() {
+	if las.columnErrs == 0 {
+		las.columnLine = las.currentLine
+	}
+	las.columnErrs++
+}
+
+// storeDataRow - parse one row of data section and store values to curves
+// fields[0] - depth, other fields - values of curves in order of section ~C
+func (las *Las) storeDataRow(fields []string) {
+	if !las.parseDataRow(fields, &las.row) {
+		return
+	}
+	// in data section currentLine is index in rows, lineNo of parameters is number of line
+	las.pointLines = append(las.pointLines, lineRange{las.recFirst + 1, las.currentLine + 1})
+	for j := range las.Logs { // цикл по каротажам
+		las.Logs[j].D = append(las.Logs[j].D, las.row.V[0])
+		las.Logs[j].V = append(las.Logs[j].V, las.row.V[j])
+		if las.Logs[j].IsString() {
+			las.Logs[j].S = append(las.Logs[j].S, las.row.S[j])
+		}
+	}
+}
+
+// parseDataRow - convert one row of data section to numbers and store to row, all warnings added to las
+// fields[0] - depth, other fields - values of curves in order of section ~C
+// return false if row must be ignored
+func (las *Las) parseDataRow(fields []string, row *LasRow) bool {
+	var (
+		v    float64
+		err  error
+		dept float64
+	)
+	n := len(las.Logs)
+	if n == 0 { // curves not defined, possible if check CURV disabled
+		las.addWarning(newWarning(WarnCurveSecEmpty, lasSecData, las.currentLine, "curves not defined, line ignore"))
+		return false
+	}
+	//line must have n columns
+	if len(fields) == 0 { // empty line: warning and ignore
+		las.addWarning(newWarning(WarnDataEmptyLine, lasSecData, las.currentLine, "wow this happened, the line is empty, ignore"))
+		return false
+	}
+	if len(fields) != n {
+		las.addColumnLine()
+		las.addWarning(newWarning(WarnDataColumnCount, lasSecData, las.currentLine, "line contains %d columns, expected: %d", len(fields), n))
+		if las.strict {
+			j := n // first extra value or position after last value
+			if len(fields) < n {
+				j = len(fields)
 			}
-			v, err = strconv.ParseFloat(s, 64)
-			if err != nil {
-				las.addWarning(TWarning{directOnRead, lasSecData, las.currentLine, fmt.Sprintf("error convert string: '%s' to number, set to NULL", s)})
-				v = las.NULL()
+			las.dataViolation(j, "line contains %d values, expected: %d", len(fields), n)
+			return false
+		}
+	}
+	if (len(fields) < n) && !las.repairEnabled(RepairMissing) {
+		las.addWarning(newWarning(WarnDataMissing, lasSecData, las.currentLine, "for %d columns data not present, line ignore", n-len(fields)))
+		return false
+	}
+	// we will analyze the first column separately to check for monotony, and if occure error on parse first column then all line ignore
+	dept, err = parseIndex(fields[0])
+	if err != nil {
+		las.addWarning(newWarning(WarnDataIndex, lasSecData, las.currentLine, "dept:'%s' not numeric, line ignore", fields[0]))
+		las.dataViolation(0, "depth '%s' not numeric", fields[0])
+		return false
+	}
+	// проверка монотонности шага
+	if cr := las.deptMonotony(dept); !cr.Res {
+		las.addWarning(cr.Warning)
+	}
+	row.reset(n)
+	row.Line = las.currentLine
+	row.V[0] = dept //TODO надо подумать про колонку значений для кривой DEPT
+
+	nullAsStr := strconv.FormatFloat(las.NULL(), 'f', 5, 64) // Null as string
+
+	for j := 1; j < n; j++ { // цикл по каротажам
+		s := ""
+		if j >= len(fields) {
+			s = nullAsStr // columns count in current line less than curves count, fill as null value
+			las.addWarning(newWarning(WarnDataMissing, lasSecData, las.currentLine, "for column %d data not present, value set to NULL", j+1))
+			las.addMissingRepair(RepairRecord{RepairMissing, las.currentLine + 1, las.Logs[j].Name, "", s, "data not present, value set to NULL"}, 1)
+		} else {
+			s = fields[j]
+		}
+		if las.Logs[j].IsString() {
+			row.S[j] = s
+			row.V[j] = las.NULL()
+			continue
+		}
+		v, err = las.parseDataValue(s)
+		if err != nil {
+			las.addWarning(newWarning(WarnDataNotNumeric, lasSecData, las.currentLine, "error convert string: '%s' to number, set to NULL", s))
+			if las.strict {
+				las.dataViolation(j, "value '%s' not numeric", s)
+				return false
 			}
-			las.Logs[j].D = append(las.Logs[j].D, dept)
-			las.Logs[j].V = append(las.Logs[j].V, v)
 		}
+		row.V[j] = v
 	}
-	return las.NumPoints(), nil
+	return true
+}
+
+// parseDataValue - convert one value from data section to number
+// empty string (possible with DLM COMMA or TAB) is NULL
+// on error return NULL and error
+func (las *Las) parseDataValue(s string) (float64, error) {
+	if len(s) == 0 {
+		return las.NULL(), nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return las.NULL(), err
+	}
+	return v, nil
 }
 
 // NumPoints - return actually number of points in data
@@ -425,8 +871,8 @@ func (las *Las) Save(fileName string, useMnemonic ...bool) error {
 	return nil
 }
 
-// SaveToBuf - save to file
-// rewrite if file exist
+// SaveToBuf - save to buffer
+// version of output las file 1.2, 2.0 or 3.0, specify by SetSaveVersion()
 // if useMnemonic == true then on save using std mnemonic on ~Curve section
 // ir return err != nil then fatal error, returned slice is not full corrected
 func (las *Las) SaveToBuf(useMnemonic bool) ([]byte, error) {
@@ -434,18 +880,37 @@ func (las *Las) SaveToBuf(useMnemonic bool) ([]byte, error) {
 	if n <= 0 {
 		return nil, errors.New("logs not exist")
 	}
+	las.storeWellInfo()
 	var b bytes.Buffer
-	fmt.Fprint(&b, _LasFirstLine)
-	fmt.Fprintf(&b, _LasVersion, 2.0) //file is always saved in 2.0 format
-	fmt.Fprint(&b, _LasWrap)
-	fmt.Fprint(&b, _LasWellInfoSec)
-	fmt.Fprintf(&b, _LasStrt, las.STRT())
-	fmt.Fprintf(&b, _LasStop, las.STOP())
-	fmt.Fprintf(&b, _LasStep, las.STEP())
-	fmt.Fprintf(&b, _LasNull, las.NULL())
-	fmt.Fprintf(&b, _LasWell, las.WELL())
-	fmt.Fprint(&b, _LasCurvSec)
-	fmt.Fprint(&b, _LasCurvDept)
+	cp := las.oCodepage
+	switch {
+	case las.lossless && (las.header != nil):
+		las.saveLossless(&b)
+		cp = las.iCodepage
+	case las.oVersion >= 3.0:
+		las.save30(&b, useMnemonic)
+	default:
+		las.save20(&b, useMnemonic)
+	}
+	r, _ := cpd.NewReaderTo(io.Reader(&b), cp.String()) //ошибку не обрабатываем, допустимость oCodepage проверяем раньше, других причин нет
+	bufToSave, _ := ioutil.ReadAll(r)
+	return bufToSave, nil
+}
+
+// save20 - write las 2.0 file to buffer
+func (las *Las) save20(b *bytes.Buffer, useMnemonic bool) {
+	n := len(las.Logs) //log count
+	fmt.Fprint(b, _LasFirstLine)
+	fmt.Fprintf(b, _LasVersion, math.Min(las.oVersion, 2.0))
+	if las.oWrapWidth > 0 {
+		fmt.Fprint(b, _LasWrapYes)
+	} else {
+		fmt.Fprint(b, _LasWrap)
+	}
+	las.saveVerSec(b)
+	las.saveWelSec(b)
+	fmt.Fprint(b, _LasCurvSec)
+	fmt.Fprintf(b, _LasCurvLine, las.indexName(), unitField(las.IndexUnit()), las.Logs[0].API, las.Logs[0].Desc)
 
 	for i := 1; i < n; i++ { //Пишем названия каротажей
 		l := las.Logs[i]
@@ -454,21 +919,184 @@ func (las *Las) SaveToBuf(useMnemonic bool) ([]byte, error) {
 				l.Name = l.Mnemonic
 			}
 		}
-		fmt.Fprintf(&b, _LasCurvLine, l.Name, l.Unit) //запись мнемоник в секции ~Curve
+		fmt.Fprintf(b, _LasCurvLine, l.saveName(), unitField(l.Unit), l.API, l.Desc) //запись мнемоник в секции ~Curve
+	}
+	las.saveParSec(b)
+	las.saveOthSec(b, _LasOtherSec)
+	fmt.Fprint(b, _LasDataSec)
+	if las.oWrapWidth > 0 {
+		las.saveWrapData(b)
+	} else {
+		las.saveData(b)
+	}
+}
+
+// saveIndexParam - write STRT, STOP or STEP with unit of index
+// for date-time index STRT, STOP written in ISO 8601 format, STEP in seconds
+// description taken from parameter, if parameter not exist standard description written
+func (las *Las) saveIndexParam(b *bytes.Buffer, name string) {
+	unit := las.IndexUnit()
+	p, ok := las.WelSec.params[name]
+	switch name {
+	case "STRT":
+		if !ok {
+			p.Desc = "START " + las.indexCaption()
+		}
+		fmt.Fprintf(b, _LasStrt, unit, las.formatIndex(las.STRT(), ""), p.Desc)
+	case "STOP":
+		if !ok {
+			p.Desc = "STOP  " + las.indexCaption()
+		}
+		fmt.Fprintf(b, _LasStop, unit, las.formatIndex(las.STOP(), ""), p.Desc)
+	case "STEP":
+		if las.IndexType() == IndexDateTime {
+			unit = "S"
+		}
+		if !ok {
+			p.Desc = "STEP"
+		}
+		fmt.Fprintf(b, _LasStep, unit, strconv.FormatFloat(las.STEP(), 'f', -1, 64), p.Desc)
+	}
+}
+
+// saveVerSec - write parameters of section ~V except VERS, WRAP, DLM, it written by caller
+func (las *Las) saveVerSec(b *bytes.Buffer) {
+	for _, p := range las.VerSec.sortedParams() {
+		switch p.Name {
+		case "VERS", "WRAP", "DLM":
+			continue
+		}
+		fmt.Fprintf(b, _LasParamLine, p.saveName(), p.Unit, p.Val, p.Desc)
+	}
+}
+
+// saveWelSec - write section ~W, all parameters in order of source file
+// STRT, STOP, STEP, NULL, WELL always written, if they not exist or added by program they placed first
+func (las *Las) saveWelSec(b *bytes.Buffer) {
+	fmt.Fprint(b, _LasWellInfoSec)
+	first := make(map[string]bool)
+	for _, p := range []HeaderParam{{Name: "STRT"}, {Name: "STOP"}, {Name: "STEP"}, {Name: "NULL", Desc: "NULL VALUE"}, {Name: "WELL", Desc: "WELL"}} {
+		if q, ok := las.WelSec.params[p.Name]; ok {
+			if q.lineNo > 0 {
+				continue
+			}
+			p = q
+		}
+		first[p.Name] = true
+		las.saveWelParam(b, p)
+	}
+	for _, p := range las.WelSec.sortedParams() {
+		if !first[p.Name] {
+			las.saveWelParam(b, p)
+		}
+	}
+}
+
+// saveWelParam - write parameter of section ~W
+// for las 1.2 parameters with not numeric value written after colon, layout of source las 1.2 kept
+func (las *Las) saveWelParam(b *bytes.Buffer, p HeaderParam) {
+	if (las.oVersion < 2.0) && p.valueAfterColon() {
+		fmt.Fprintf(b, _LasParamLine, p.saveName(), p.Unit, p.Desc, p.Val)
+		return
+	}
+	switch p.Name {
+	case "STRT", "STOP", "STEP":
+		las.saveIndexParam(b, p.Name)
+	case "NULL":
+		fmt.Fprintf(b, _LasNull, las.NULL(), p.Desc)
+	case "WELL":
+		fmt.Fprintf(b, _LasWell, las.WELL(), p.Desc)
+	default:
+		t, ok := wellTemplates[p.Name]
+		if !ok {
+			fmt.Fprintf(b, _LasParamLine, p.saveName(), p.Unit, p.Val, p.Desc)
+			return
+		}
+		if len(p.Desc) == 0 {
+			p.Desc = t[1]
+		}
+		switch p.Name {
+		case "XWELL", "YWELL", "RKB":
+			fmt.Fprintf(b, t[0], p.Unit, p.Val, p.Desc)
+		default:
+			fmt.Fprintf(b, t[0], p.Val, p.Desc)
+		}
+	}
+}
+
+// saveParSec - write section ~P, parameters in order of source file
+func (las *Las) saveParSec(b *bytes.Buffer) {
+	if len(las.ParSec.params) == 0 {
+		return
 	}
-	fmt.Fprint(&b, _LasDataSec)
-	fmt.Fprintf(&b, "%s\n", las.Logs.Captions()) //write comment with curves name
-	//write data
+	fmt.Fprint(b, _LasParamSec)
+	for _, p := range las.ParSec.sortedParams() {
+		fmt.Fprintf(b, _LasParamLine, p.saveName(), p.Unit, p.Val, p.Desc)
+	}
+}
+
+// saveOthSec - write section ~O as is, title - title of section
+func (las *Las) saveOthSec(b *bytes.Buffer, title string) {
+	if len(las.OthSec.params) == 0 {
+		return
+	}
+	fmt.Fprint(b, title)
+	for _, p := range las.OthSec.sortedParams() {
+		fmt.Fprintf(b, "%s\n", p.Val)
+	}
+}
+
+// indexName - return name of index curve for save, depth always saved as DEPT
+func (las *Las) indexName() string {
+	if (las.IndexType() == IndexDepth) || (len(las.Logs) == 0) {
+		return "DEPT"
+	}
+	return las.Logs[0].Name
+}
+
+// saveData - write data section, one line per depth step
+func (las *Las) saveData(b *bytes.Buffer) {
+	fmt.Fprintf(b, "%s\n", las.Logs.Captions()) //write comment with curves name
+
+	for i := 0; i < las.NumPoints(); i++ { //loop by dept (.)
+		las.saveRecord(b, i)
+	}
+}
+
+// saveRecord - write one line of data section
+func (las *Las) saveRecord(b *bytes.Buffer, i int) {
+	fmt.Fprintf(b, "%-10s ", las.formatIndex(las.Logs[0].D[i], "%.4f"))
+	for j := 1; j < len(las.Logs); j++ { //loop by logs
+		fmt.Fprintf(b, "%-10.4f ", las.Logs[j].V[i])
+	}
+	fmt.Fprintln(b)
+}
+
+// saveWrapData - write data section for wrapped file
+// depth alone on first line of record, values of curves wrapped to lines with length not more then las.oWrapWidth
+// line always contains at least one value, even if value longer then width
+func (las *Las) saveWrapData(b *bytes.Buffer) {
 	for i := 0; i < las.NumPoints(); i++ { //loop by dept (.)
-		fmt.Fprintf(&b, "%-10.4f ", las.Logs[0].D[i])
-		for j := 1; j < n; j++ { //loop by logs
-			fmt.Fprintf(&b, "%-10.4f ", las.Logs[j].V[i])
+		las.saveWrapRecord(b, i, las.oWrapWidth)
+	}
+}
+
+// saveWrapRecord - write one record of wrapped data section, lines not longer then width
+func (las *Las) saveWrapRecord(b *bytes.Buffer, i, width int) {
+	fmt.Fprintf(b, "%s\n", las.formatIndex(las.Logs[0].D[i], "%.4f"))
+	lineLen := 0
+	for j := 1; j < len(las.Logs); j++ { //loop by logs
+		s := fmt.Sprintf(" %10.4f", las.Logs[j].V[i])
+		if (lineLen > 0) && (lineLen+len(s) > width) {
+			fmt.Fprintln(b)
+			lineLen = 0
 		}
-		fmt.Fprintln(&b)
+		b.WriteString(s)
+		lineLen += len(s)
+	}
+	if lineLen > 0 {
+		fmt.Fprintln(b)
 	}
-	r, _ := cpd.NewReaderTo(io.Reader(&b), las.oCodepage.String()) //ошибку не обрабатываем, допустимость oCodepage проверяем раньше, других причин нет
-	bufToSave, _ := ioutil.ReadAll(r)
-	return bufToSave, nil
 }
 
 // IsEmpty - test to not initialize object
@@ -477,93 +1105,99 @@ func (las *Las) IsEmpty() bool {
 }
 
 // GetStrtFromData - return strt from data section
-// read 1 line from section ~A and determine strt
-// close file
+// first index value taken from lines of section ~A already read to rows
 // return Null if error occurs
 func (las *Las) GetStrtFromData() float64 {
-	iFile, err := os.Open(las.FileName)
-	if err != nil {
-		return las.NULL() //не обрабатывается в тесте
-	}
-	defer iFile.Close()
-
-	_, iScanner, err := xlib.SeekFileStop(las.FileName, "~A")
-	if (err != nil) || (iScanner == nil) {
-		return las.NULL() //не обрабатывается в тесте
-	}
-
-	s := ""
-	dept1 := 0.0
-	for i := 0; iScanner.Scan(); i++ {
-		s = strings.TrimSpace(iScanner.Text())
-		if (len(s) == 0) || (s[0] == '#') {
-			continue //не обрабатывается в тесте
-		}
-		k := strings.IndexRune(s, ' ')
-		if k < 0 {
-			k = len(s)
-		}
-		dept1, err = strconv.ParseFloat(s[:k], 64)
-		if err != nil {
-			return las.NULL() //не обрабатывается в тесте
-		}
-		return dept1
+	index := las.indexFromData(1)
+	if len(index) < 1 {
+		return las.NULL()
 	}
-	//если мы попали сюда, то всё грусно, в файле после ~A не нашлось двух строчек с данными... или пустые строчки или комменты
-	return las.NULL()
+	return index[0]
 }
 
 // GetStepFromData - return step from data section
-// read 2 line from section ~A and determine step
-// close file
+// step determined by all index values from lines of section ~A already read to rows, see IndexStep()
+// for irregular index return 0
 // return Null if error occure
 func (las *Las) GetStepFromData() float64 {
-	iFile, err := os.Open(las.FileName)
-	if err != nil {
-		return las.NULL() //не обрабатывается в тесте
-	}
-	defer iFile.Close()
-
-	_, iScanner, err := xlib.SeekFileStop(las.FileName, "~A")
-	if (err != nil) || (iScanner == nil) {
+	step, kind := IndexStep(las.indexFromData(-1))
+	switch kind {
+	case IndexUnknown:
+		//bad case, data section not contain two rows with different depth
 		return las.NULL()
+	case IndexIrregular:
+		return 0
 	}
+	return step
+}
 
-	s := ""
-	j := 0
-	dept1 := 0.0
-	dept2 := 0.0
-	for i := 0; iScanner.Scan(); i++ {
-		s = strings.TrimSpace(iScanner.Text())
+// indexFromData - return first values of index (depth) from lines of main data section stored in rows
+// max - maximum count of values, if max < 0 return all
+// reading stops at line with not a number index, or at next section (las 3.0)
+// for wrapped file index taken from first line of each record
+func (las *Las) indexFromData(max int) []float64 {
+	index := make([]float64, 0)
+	if (las.dataStart <= 0) || (las.dataStart > len(las.rows)) {
+		return index
+	}
+	dlm := las.DLM()
+	wrap := las.IsWraped()
+	k := 0 // fields remaining in current wrapped record
+	for _, s := range las.rows[las.dataStart:] {
+		s = strings.TrimSpace(s)
 		if isIgnoredLine(s) {
 			continue
 		}
-		k := strings.IndexRune(s, ' ')
-		if k < 0 {
-			k = len(s)
+		if s[0] == '~' {
+			break
+		}
+		fields := splitDataLine(s, dlm)
+		if wrap && (k > 0) {
+			k -= len(fields)
+			continue
 		}
-		dept1, err = strconv.ParseFloat(s[:k], 64)
+		v, err := parseIndex(fields[0])
 		if err != nil {
 			// case if the data row in the first position (dept place) contains not a number
-			return las.NULL()
+			break
 		}
-		j++
-		if j == 2 {
-			// good case, found two points and determined the step
-			return math.Round((dept1-dept2)*10) / 10
+		index = append(index, v)
+		if (max >= 0) && (len(index) >= max) {
+			break
+		}
+		if wrap {
+			k = len(las.Logs) - len(fields)
 		}
-		dept2 = dept1
 	}
-	//bad case, data section not contain two rows with depth
-	return las.NULL() //не обрабатывается в тесте
+	return index
 }
 
 func (las *Las) setStep(h float64) {
-	las.WelSec.params["STEP"] = HeaderParam{strconv.FormatFloat(h, 'f', -1, 64), "STEP", "", "", "", "step of index", 8}
+	las.setIndexParam(HeaderParam{Val: strconv.FormatFloat(h, 'f', -1, 64), Name: "STEP", Desc: "step of index"})
 }
 
 func (las *Las) setStrt(strt float64) {
-	las.WelSec.params["STRT"] = HeaderParam{strconv.FormatFloat(strt, 'f', -1, 64), "STRT", "", "", "", "first index value", 6}
+	las.setIndexParam(HeaderParam{Val: strconv.FormatFloat(strt, 'f', -1, 64), Name: "STRT", Desc: "first index value"})
+}
+
+func (las *Las) setStop(stop float64) {
+	s := strconv.FormatFloat(stop, 'f', -1, 64)
+	if las.IndexType() == IndexDateTime {
+		s = las.formatIndex(stop, "")
+	}
+	las.setIndexParam(HeaderParam{Val: s, Name: "STOP", Desc: "last index value"})
+}
+
+// setIndexParam - store parameter STRT, STOP or STEP, unit, description and line of existing parameter are kept
+// not existing parameter added without line, on lossless save it written as new line
+func (las *Las) setIndexParam(p HeaderParam) {
+	old, ok := las.WelSec.params[p.Name]
+	if !ok {
+		las.WelSec.Set(p)
+		return
+	}
+	p.IName, p.Unit, p.Mnemonic, p.Desc, p.lineNo, p.order = old.IName, old.Unit, old.Mnemonic, old.Desc, old.lineNo, old.order
+	las.WelSec.params[p.Name] = p
 }
 
 // IsStrtEmpty - return true if parameter Strt not exist in file
@@ -590,7 +1224,7 @@ func (las *Las) SetNull(null float64) {
 			}
 		}
 	}
-	las.WelSec.params["NULL"] = HeaderParam{strconv.FormatFloat(null, 'f', -1, 64), "NULL", "", "", "", "null value", las.WelSec.params["NULL"].lineNo}
+	las.WelSec.params["NULL"] = HeaderParam{Val: strconv.FormatFloat(null, 'f', -1, 64), Name: "NULL", Desc: "null value", lineNo: las.WelSec.params["NULL"].lineNo}
 }
 
 // SaveWarning - save to file all warning
@@ -638,7 +1272,7 @@ func (las *Las) addWarning(w TWarning) {
 	if las.Warnings.Count() < las.maxWarningCount {
 		las.Warnings = append(las.Warnings, w)
 		if las.Warnings.Count() == las.maxWarningCount {
-			las.Warnings = append(las.Warnings, TWarning{0, 0, -1, "*maximum count* of warning reached, change parameter 'maxWarningCount' in 'glas.ini'"})
+			las.Warnings = append(las.Warnings, TWarning{0, 0, -1, "*maximum count* of warning reached, change parameter 'maxWarningCount' in 'glas.ini'", WarnLimit, SeverityInfo})
 		}
 	}
 }
@@ -3,35 +3,73 @@
 
 package glasio
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
 
-// CheckRes - результаты проверки, получааем из функции doCheck()
-// если проверка не прошла, то res будет false и
-// в warning будет положено предупреждение для логов
-// в критических случаях err != nil и в себе содержит сообщение, при этом warning содержит соответствующее предупреждение для логов
-// если err == nil то это не критичная проверка
+// CheckRes - результаты проверки, получааем из функции Check.Do()
+// если проверка не прошла, то Res будет false и
+// в Warning будет положено предупреждение для логов
+// в критических случаях Err != nil и в себе содержит сообщение, при этом Warning содержит соответствующее предупреждение для логов
+// если Err == nil то это не критичная проверка
 type CheckRes struct {
-	name    string
-	warning TWarning
-	err     error
-	res     bool
+	Name    string
+	Warning TWarning
+	Err     error // fatal error, Load() return *LoadError with this error
+	Res     bool  // true if check passed
 }
 
 func (cr CheckRes) String() string {
-	return fmt.Sprintf("check name: %s, result: %v", cr.name, cr.res)
+	return fmt.Sprintf("check name: %s, result: %v", cr.Name, cr.Res)
 }
 
-type doCheck func(chk Check, las *Las) CheckRes
+// CheckFunc - function of check, return result of check with warning
+type CheckFunc func(chk Check, las *Las) CheckRes
 
-// Check - конкретная проверка, обязан реализовать функцию doCheck()
+// Check - конкретная проверка, обязан реализовать функцию CheckFunc
 type Check struct {
-	name    string
-	section string
-	message string
-	do      doCheck
+	Name     string // name of check, key in Checker: STEP, NULL ...
+	Section  string // checked section: ~V, ~W, ~C
+	Message  string // description of check, used as text of warning by NewCheck()
+	Do       CheckFunc
+	Disabled bool // disabled check not performed
+	Data     bool // check of data, performed after load of data section
 }
 
-// CheckResults - map с результатами всех проверок
+// NewCheck - create user check
+// ok - return true if las passed check, on fail warning with code Name and text Message added to las.Warnings
+func NewCheck(name, section, message string, ok func(las *Las) bool) Check {
+	return Check{name, section, message, func(chk Check, las *Las) CheckRes {
+		return CheckRes{chk.Name, newWarning(WarningCode(chk.Name), sectionNumber(chk.Section), las.currentLine, "%s", chk.Message), nil, ok(las)}
+	}, false, false}
+}
+
+// NewDataCheck - create user check of data, performed after load of data section
+func NewDataCheck(name, section, message string, ok func(las *Las) bool) Check {
+	chk := NewCheck(name, section, message, ok)
+	chk.Data = true
+	return chk
+}
+
+// sectionNumber - return number of section for warnings by name of section: ~V, ~W, ~C, ~A
+func sectionNumber(section string) int {
+	switch strings.ToUpper(strings.TrimPrefix(section, "~")) {
+	case "V":
+		return lasSecVersion
+	case "W":
+		return lasSecWellInfo
+	case "C":
+		return lasSecCurInfo
+	case "A":
+		return lasSecData
+	}
+	return lasSecIgnore
+}
+
+// CheckResults - map с результатами всех проверок, key - name of check, contains only failed checks
 type CheckResults map[string]CheckRes
 
 func (crs CheckResults) nullWrong() bool {
@@ -70,31 +108,80 @@ func (crs CheckResults) wellWrong() bool {
 	return ok
 }
 
-// fatal - return first not nil error if CheckResults contains at least one check result with fatal error
-func (crs CheckResults) fatal() error {
-	for _, c := range crs {
-		if c.err != nil {
-			return c.err
+// fatal - return check result with fatal error first in order of names, false if CheckResults not contains fatal errors
+func (crs CheckResults) fatal() (CheckRes, bool) {
+	names := make([]string, 0, len(crs))
+	for key := range crs {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		if c := crs[key]; c.Err != nil {
+			return c, true
 		}
 	}
-	return nil
+	return CheckRes{}, false
 }
 
 // Checker - ПРОВЕРЩИК, содержит в себе всех отдельных проверщиков,
-// методом check() вызавает последовательно всех своих проверщиков,
-// результаты отправляет в Logger
+// методом Check() вызавает последовательно всех своих проверщиков,
+// проверщик передаётся в Load() через Las.SetChecker(), по умолчанию используется NewStdChecker()
 type Checker map[string]Check
 
+// Add - add check, check with the same name replaced
+func (c Checker) Add(chk Check) {
+	c[chk.Name] = chk
+}
+
+// Enable - enable check by name, return false if check not exist
+func (c Checker) Enable(name string) bool {
+	return c.setDisabled(name, false)
+}
+
+// Disable - disable check by name, return false if check not exist
+// disabled standard check also disable repair: for disabled NULL check, NULL = 0 not replaced
+func (c Checker) Disable(name string) bool {
+	return c.setDisabled(name, true)
+}
+
+func (c Checker) setDisabled(name string, disabled bool) bool {
+	chk, ok := c[name]
+	if ok {
+		chk.Disabled = disabled
+		c[name] = chk
+	}
+	return ok
+}
+
+// Check - perform all enabled checks of header in order of names
 // возвращает map в который сложены результаты проверки которые дали ошибку
 // если проверка прошла безошибочно, то её в результатах не будет
 // полученный map содержит только ошибки
-func (c Checker) check(las *Las) CheckResults {
+func (c Checker) Check(las *Las) CheckResults {
+	return c.run(las, false)
+}
+
+// CheckData - perform all enabled checks of data in order of names, result as Check()
+func (c Checker) CheckData(las *Las) CheckResults {
+	return c.run(las, true)
+}
+
+func (c Checker) run(las *Las, data bool) CheckResults {
 	res := make(CheckResults)
+	names := make([]string, 0, len(c))
+	for key := range c {
+		names = append(names, key)
+	}
+	sort.Strings(names)
 	// key - имя проверки
 	// chk - сам проверщик
-	for key, chk := range c {
-		r := chk.do(chk, las)
-		if !r.res {
+	for _, key := range names {
+		chk := c[key]
+		if chk.Disabled || (chk.Data != data) {
+			continue
+		}
+		r := chk.Do(chk, las)
+		if !r.Res {
 			res[key] = r
 		}
 	}
@@ -102,60 +189,259 @@ func (c Checker) check(las *Las) CheckResults {
 }
 
 // NewStdChecker - создание нового ПРОВЕРЩИКА las файла.
-// WRAP = ON
+// checks of header:
+// WRAP not YES or NO
 // section ~Curve is empty
 // STEP == 0
 // NULL == 0
 // STRT == STOP
 // WELL is empty
+// checks of data:
+// STOP equal to last depth, STEP match step of data, number of columns equal to number of curves in all lines,
+// depth strictly monotonic, data not contain common null values (-9999, -999 ...) other than NULL
 func NewStdChecker() Checker {
 	return Checker{
-		"WRAP": Check{"WRAP", "~V", "WRAP = ON", wrapCheck},
-		"CURV": Check{"CURV", "~C", "Curve section is empty", curvesIsEmpty},
-		"STEP": Check{"STEP", "~W", "STEP = 0", stepCheck},
-		"STPU": Check{"STPU", "~W", "STEP not exist", stepExistCheck},
-		"NULL": Check{"NULL", "~W", "NULL = 0", nullCheck},
-		"SSTP": Check{"SSTP", "~W", "STRT = STOP", strtStop},
-		"WELL": Check{"WELL", "~W", "WELL = ''", wellIsEmpty},
-		"STRT": Check{"STRT", "~W", "STRT not exist", strtExistCheck},
-		"STOP": Check{"STOP", "~W", "STOP not exist", stopExistCheck},
+		"WRAP": Check{"WRAP", "~V", "WRAP not YES or NO", wrapCheck, false, false},
+		"CURV": Check{"CURV", "~C", "Curve section is empty", curvesIsEmpty, false, false},
+		"STEP": Check{"STEP", "~W", "STEP = 0", stepCheck, false, false},
+		"STPU": Check{"STPU", "~W", "STEP not exist", stepExistCheck, false, false},
+		"NULL": Check{"NULL", "~W", "NULL = 0", nullCheck, false, false},
+		"SSTP": Check{"SSTP", "~W", "STRT = STOP", strtStop, false, false},
+		"WELL": Check{"WELL", "~W", "WELL = ''", wellIsEmpty, false, false},
+		"STRT": Check{"STRT", "~W", "STRT not exist", strtExistCheck, false, false},
+		"STOP": Check{"STOP", "~W", "STOP not exist", stopExistCheck, false, false},
+		"DSTP": Check{"DSTP", "~W", "STOP not equal to last depth", stopDataCheck, false, true},
+		"DSTE": Check{"DSTE", "~W", "STEP not match step of data", stepDataCheck, false, true},
+		"DCOL": Check{"DCOL", "~A", "number of columns not equal to number of curves", columnsCheck, false, true},
+		"DMON": Check{"DMON", "~A", "depth not strictly monotonic", monotonyCheck, false, true},
+		"DNUL": Check{"DNUL", "~A", "data contain null value other than NULL", nullDataCheck, false, true},
 	}
 }
 
 // результат проверки возвращаем всегда с готовым варнингом и ошибкой,
 // уже в дальнейшем, те проверки у которых res == true не вносятся в итоговый отчёт
 func stepExistCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, "__WRN__ parameter STEP not exist"}, nil, !las.IsStepEmpty()}
+	return CheckRes{chk.Name, newWarning(WarnStepMissing, lasSecWellInfo, las.currentLine, "parameter STEP not exist"), nil, !las.IsStepEmpty()}
 }
 
 func stopExistCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, "__WRN__ parameter STOP not exist"}, nil, !las.IsStopEmpty()}
+	return CheckRes{chk.Name, newWarning(WarnStopMissing, lasSecWellInfo, las.currentLine, "parameter STOP not exist"), nil, !las.IsStopEmpty()}
 }
 
 func strtExistCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, "__WRN__ parameter STRT not exist"}, nil, !las.IsStrtEmpty()}
+	return CheckRes{chk.Name, newWarning(WarnStrtMissing, lasSecWellInfo, las.currentLine, "parameter STRT not exist"), nil, !las.IsStrtEmpty()}
 }
 
+// wrapped files are supported, check only that the value of WRAP is valid
 func wrapCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecVersion, las.currentLine, "__ERR__ WRAP = YES, file ignored"}, fmt.Errorf("Wrapped files not support"), !las.IsWraped()}
+	w := strings.ToUpper(strings.TrimSpace(las.WRAP()))
+	return CheckRes{chk.Name, newWarning(WarnWrapInvalid, lasSecVersion, las.currentLine, "WRAP: '%s' must be YES or NO", las.WRAP()), nil, (w == "YES") || (w == "NO")}
 }
 
 func curvesIsEmpty(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecCurInfo, las.currentLine, "__ERR__ Curve section is empty, file ignored"}, fmt.Errorf("Curve section not exist"), len(las.Logs) > 0}
+	return CheckRes{chk.Name, newWarning(WarnCurveSecEmpty, lasSecCurInfo, las.currentLine, "Curve section is empty, file ignored"), ErrNoCurves, len(las.Logs) > 0}
 }
 
 func stepCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, fmt.Sprint("__WRN__ STEP parameter equal 0")}, nil, las.STEP() != 0.0}
+	return CheckRes{chk.Name, newWarning(WarnStepZero, lasSecWellInfo, las.currentLine, "STEP parameter equal 0"), nil, las.STEP() != 0.0}
 }
 
 func nullCheck(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, fmt.Sprint("__WRN__ NULL parameter equal 0")}, nil, las.NULL() != 0.0}
+	return CheckRes{chk.Name, newWarning(WarnNullZero, lasSecWellInfo, las.currentLine, "NULL parameter equal 0"), nil, las.NULL() != 0.0}
 }
 
 func strtStop(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, fmt.Sprintf("__WRN__ STRT: %4.3f == STOP: %4.3f", las.STRT(), las.STOP())}, nil, las.STRT() != las.STOP()}
+	return CheckRes{chk.Name, newWarning(WarnStrtEqualStop, lasSecWellInfo, las.currentLine, "STRT: %4.3f == STOP: %4.3f", las.STRT(), las.STOP()), nil, las.STRT() != las.STOP()}
 }
 
 func wellIsEmpty(chk Check, las *Las) CheckRes {
-	return CheckRes{chk.name, TWarning{directOnRead, lasSecWellInfo, las.currentLine, fmt.Sprintf("__WRN__ WELL: '%s' is empty", las.WELL())}, nil, len(las.WELL()) != 0}
+	return CheckRes{chk.Name, newWarning(WarnWellEmpty, lasSecWellInfo, las.currentLine, "WELL: '%s' is empty", las.WELL()), nil, len(las.WELL()) != 0}
+}
+
+// checks of data, performed after load of data section, line of warning is line of parameter or of first wrong line
+
+// indexTolerance - tolerance on compare of index values: part of STEP, for STEP = 0 part of STOP
+func (las *Las) indexTolerance() float64 {
+	if las.STEP() != 0 {
+		return math.Abs(las.STEP()) * StepTolerance
+	}
+	return math.Max(math.Abs(las.STOP())*1e-9, 1e-9)
+}
+
+// paramLine - return line of parameter of section ~W for warning
+func (las *Las) paramLine(name string) int {
+	return las.WelSec.params[name].lineNo - 1
+}
+
+// pointLine - return line of data point i for warning
+func (las *Las) pointLine(i int) int {
+	if i < len(las.pointLines) {
+		return las.pointLines[i].first - 1
+	}
+	return las.currentLine
+}
+
+// dataStat - summary of data section used by checks of data
+// on Load collected from loaded curves after repairs, on LoadStream row by row
+type dataStat struct {
+	points    int       // number of rows
+	last      float64   // last depth
+	diff      []float64 // not zero differences of depth, while base step not determined
+	headSize  int       // number of differences to determine base step, 0 - all differences stored
+	step      stepStat  // running estimation of step, used after headSize differences
+	running   bool      // base step determined, step estimated by stepStat
+	sign      float64   // direction of depth: 1 or -1, 0 - not determined
+	wrong     int       // number of depths breaking monotony
+	wrongDept float64   // first depth breaking monotony
+	wrongPrev float64   // depth before first depth breaking monotony
+	wrongLine int       // line of first depth breaking monotony
+	nulls     int       // number of common null values other than NULL
+	nullValue float64   // first common null value
+	nullLine  int       // line of first common null value
+}
+
+// add - add row of data to summary, v[0] - depth, line - line of row for warning
+func (st *dataStat) add(las *Las, v []float64, line int) {
+	dept := v[0]
+	if st.points > 0 {
+		diff := dept - st.last
+		if (st.sign == 0) && (diff != 0) {
+			st.sign = math.Copysign(1, diff) // direction taken from first not zero difference
+		}
+		if (diff == 0) || (math.Signbit(diff) != math.Signbit(st.sign)) {
+			if st.wrong == 0 {
+				st.wrongDept, st.wrongPrev, st.wrongLine = dept, st.last, line
+			}
+			st.wrong++
+		}
+		st.addDiff(diff)
+	}
+	for j := 1; j < len(v) && j < len(las.Logs); j++ {
+		if las.Logs[j].IsString() || (v[j] == las.NULL()) || !isCommonNull(v[j]) {
+			continue
+		}
+		if st.nulls == 0 {
+			st.nullValue, st.nullLine = v[j], line
+		}
+		st.nulls++
+	}
+	st.last = dept
+	st.points++
+}
+
+// addDiff - add difference of neighboring depths to estimation of step
+func (st *dataStat) addDiff(d float64) {
+	switch {
+	case d == 0:
+	case st.running:
+		st.step.add(d)
+	default:
+		st.diff = append(st.diff, d)
+		if (st.headSize > 0) && (len(st.diff) >= st.headSize) {
+			st.step = newStepStat(st.diff)
+			for _, x := range st.diff {
+				st.step.add(x)
+			}
+			st.diff, st.running = nil, true
+		}
+	}
+}
+
+// indexStep - return step and type of index as IndexStep()
+// if more than headSize differences, base step determined on first headSize differences
+func (st *dataStat) indexStep() (float64, IndexKind) {
+	if st.running {
+		return st.step.result()
+	}
+	if len(st.diff) == 0 {
+		return 0, IndexUnknown
+	}
+	step := newStepStat(st.diff)
+	for _, d := range st.diff {
+		step.add(d)
+	}
+	return step.result()
+}
+
+// loadedDataStat - summary of loaded data
+func (las *Las) loadedDataStat() *dataStat {
+	st := &dataStat{}
+	v := make([]float64, len(las.Logs))
+	for i := 0; i < las.NumPoints(); i++ {
+		v[0] = las.Logs[0].D[i]
+		for j := 1; j < len(las.Logs); j++ {
+			v[j] = las.Logs[j].V[i]
+		}
+		st.add(las, v, las.pointLine(i))
+	}
+	return st
+}
+
+// getDataStat - return summary of data collected on load, if not collected - summary of loaded data
+func (las *Las) getDataStat() *dataStat {
+	if las.stat == nil {
+		return las.loadedDataStat()
+	}
+	return las.stat
+}
+
+func stopDataCheck(chk Check, las *Las) CheckRes {
+	st := las.getDataStat()
+	ok := (st.points == 0) || (las.STOP() == las.NULL()) || (math.Abs(las.STOP()-st.last) <= las.indexTolerance())
+	last := las.NULL()
+	if st.points > 0 {
+		last = st.last
+	}
+	return CheckRes{chk.Name, newWarning(WarnDataStop, lasSecWellInfo, las.paramLine("STOP"), "STOP: %g not equal to last depth: %g", las.STOP(), last), nil, ok}
+}
+
+func stepDataCheck(chk Check, las *Las) CheckRes {
+	step, kind := las.getDataStat().indexStep()
+	ok := true
+	switch kind {
+	case IndexUnknown:
+	case IndexIrregular:
+		ok = las.STEP() == 0
+	default:
+		ok = math.Abs(las.STEP()-step) <= math.Abs(step)*StepTolerance
+	}
+	return CheckRes{chk.Name, newWarning(WarnDataStep, lasSecWellInfo, las.paramLine("STEP"), "STEP: %g not match %s index with step: %g", las.STEP(), kind, step), nil, ok}
+}
+
+func columnsCheck(chk Check, las *Las) CheckRes {
+	if las.columnErrs == 0 {
+		return CheckRes{chk.Name, TWarning{}, nil, true}
+	}
+	return CheckRes{chk.Name, newWarning(WarnDataColumns, lasSecData, las.columnLine,
+		"%d lines contain number of columns not equal to %d curves, first line: %d", las.columnErrs, len(las.Logs), las.columnLine+1), nil, false}
+}
+
+func monotonyCheck(chk Check, las *Las) CheckRes {
+	st := las.getDataStat()
+	if st.wrong == 0 {
+		return CheckRes{chk.Name, TWarning{}, nil, true}
+	}
+	return CheckRes{chk.Name, newWarning(WarnDataMonotony, lasSecData, st.wrongLine,
+		"depth not strictly monotonic, wrong points: %d, first: %g after %g", st.wrong, st.wrongDept, st.wrongPrev), nil, false}
+}
+
+// commonNulls - values used as null in las files
+var commonNulls = []float64{-999.25, -999, -9999, -9999.25, -99999, 999.25, 9999}
+
+func nullDataCheck(chk Check, las *Las) CheckRes {
+	st := las.getDataStat()
+	if st.nulls == 0 {
+		return CheckRes{chk.Name, TWarning{}, nil, true}
+	}
+	return CheckRes{chk.Name, newWarning(WarnDataNull, lasSecData, st.nullLine,
+		"data contain %d values %g, but NULL is %g", st.nulls, st.nullValue, las.NULL()), nil, false}
+}
+
+func isCommonNull(v float64) bool {
+	for _, null := range commonNulls {
+		if v == null {
+			return true
+		}
+	}
+	return false
 }
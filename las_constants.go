@@ -9,29 +9,37 @@ const (
 	_LasFirstLine      = "~Version information\n"
 	_LasVersion        = "VERS.                          %3.1f : glas (c) softlandia@gmail.com\n"
 	_LasWrap           = "WRAP.                          NO  : ONE LINE PER DEPTH STEP\n"
+	_LasWrapYes        = "WRAP.                          YES : MULTIPLE LINES PER DEPTH STEP\n"
 	_LasWellInfoSec    = "~Well information\n"
 	_LasMnemonicFormat = "#MNEM.UNIT DATA                                  :DESCRIPTION\n"
-	_LasStrt           = " STRT.M %8.3f                                    :START DEPTH\n"
-	_LasStop           = " STOP.M %8.3f                                    :STOP  DEPTH\n"
-	_LasStep           = " STEP.M %8.3f                                    :STEP\n"
-	_LasNull           = " NULL.  %9.3f                                   :NULL VALUE\n"
-	_LasRkb            = " RKB.M %8.3f                                     :KB or GL\n"
-	_LasXcoord         = " XWELL.M %8.3f                                   :Well head X coordinate\n"
-	_LasYcoord         = " YWELL.M %8.3f                                   :Well head Y coordinate\n"
-	_LasOilComp        = " COMP.  %-43.43s:OIL COMPANY\n"
-	_LasWell           = " WELL.   %-43.43s:WELL\n"
-	_LasField          = " FLD .  %-43.43s:FIELD\n"
-	_LasLoc            = " LOC .  %-43.43s:LOCATION\n"
-	_LasCountry        = " CTRY.  %-43.43s:COUNTRY\n"
-	_LasServiceComp    = " SRVC.  %-43.43s:SERVICE COMPANY\n"
-	_LasDate           = " DATE.  %-43.43s:DATE\n"
-	_LasAPI            = " API .  %-43.43s:API NUMBER\n"
-	_LasUwi            = " UWI .  %-43.43s:UNIVERSAL WELL INDEX\n"
+	_LasStrt           = " STRT.%s %8s                                    :%s\n"
+	_LasStop           = " STOP.%s %8s                                    :%s\n"
+	_LasStep           = " STEP.%s %8s                                    :%s\n"
+	_LasNull           = " NULL.  %9.3f                                   :%s\n"
+	_LasRkb            = " RKB.%s %12s                                    :%s\n"
+	_LasXcoord         = " XWELL.%s %12s                                  :%s\n"
+	_LasYcoord         = " YWELL.%s %12s                                  :%s\n"
+	_LasOilComp        = " COMP.   %-43s:%s\n"
+	_LasWell           = " WELL.   %-43s:%s\n"
+	_LasField          = " FLD .   %-43s:%s\n"
+	_LasLoc            = " LOC .   %-43s:%s\n"
+	_LasCountry        = " CTRY.   %-43s:%s\n"
+	_LasServiceComp    = " SRVC.   %-43s:%s\n"
+	_LasDate           = " DATE.   %-43s:%s\n"
+	_LasAPI            = " API .   %-43s:%s\n"
+	_LasUwi            = " UWI .   %-43s:%s\n"
 	_LasCurvSec        = "~Curve Information Section\n"
 	_LasCurvFormat     = "#MNEM.UNIT                 :DESCRIPTION\n"
-	_LasCurvDept       = " DEPT.M                    :\n"
-	_LasCurvLine       = " %s.%s                     :\n"
+	_LasCurvLine       = " %s.%s %s                     :%s\n"
 	_LasDataSec        = "~ASCII Log Data\n"
+	_LasParamSec       = "~Parameter information\n"
+	_LasOtherSec       = "~Other information\n"
+	_LasDlm            = "DLM .                          %-5s: DELIMITING CHARACTER BETWEEN DATA COLUMNS\n"
+	_LasParamLine      = " %-5s.%-10s %-30s : %s\n"
+	_LasParamLine30    = " %-5s.%-10s %-30s : %s {%s}\n"
+	_LasGroupPar       = "~%s_Parameter\n"
+	_LasGroupDef       = "~%s_Definition\n"
+	_LasGroupData      = "~%s_Data | %s_Definition\n"
 
 	//secName: 0 - empty, 1 - Version, 2 - Well info, 3 - Curve info, 4 - dAta
 	lasSecIgnore   = 0
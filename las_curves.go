@@ -1 +1,131 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// parser of curve definition from section ~C
+
 package glasio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurveDef - fields of curve definition line: "DT  .US/M     7 350 02 00 : 2  SONIC TRANSIT TIME"
+type CurveDef struct {
+	Name     string   // mnemonic, may contain dots and spaces: "GR.1", "ПС повт"
+	Unit     string   // unit, brackets removed: "DEPT.[M]" give "M"
+	API      string   // API code of las 1.2 and 2.0 or value of curve definition of las 3.0: "7 350 02 00"
+	Desc     string   // description, spaces collapsed
+	Warnings []string // ambiguities of line and how they are resolved
+}
+
+// ParseCurveDef - parse line of section ~C
+// mnemonic separated from unit by dot, if first word of line contains several dots, unit is after last dot,
+// except when text after first dot is known unit: "RT.OHM.M" is RT in OHM.M
+// unit written immediately after dot or in brackets, after unit API code, description after last colon
+// mnemonic missing - name is "-EL-", leading dot of mnemonic ".ILM .OHMM" ignored
+func ParseCurveDef(s string) CurveDef {
+	var c CurveDef
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\t", " "))
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		c.Desc = collapseSpaces(s[i+1:])
+		s = strings.TrimSpace(s[:i])
+	}
+	if strings.HasPrefix(s, ".") && !strings.HasPrefix(s, ". ") && strings.Contains(s[1:], ".") {
+		// ".ILM .OHMM" - leading dot before mnemonic, ".OHMM" - mnemonic missing
+		c.warn("mnemonic '%s' begins with dot, dot ignored", strings.Fields(s)[0])
+		s = s[1:]
+	}
+	iDot := c.unitDot(s)
+	if iDot < 0 {
+		c.Name = collapseSpaces(s)
+		if strings.Contains(c.Name, " ") {
+			c.warn("dot not found, '%s' taken as mnemonic", c.Name)
+		}
+	} else {
+		c.Name = collapseSpaces(s[:iDot])
+		c.parseUnit(s[iDot+1:])
+	}
+	if len(c.Name) == 0 {
+		c.Name = defCurveName
+		c.warn("mnemonic missing, name set to '%s'", defCurveName)
+	}
+	return c
+}
+
+func (c *CurveDef) warn(format string, a ...interface{}) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, a...))
+}
+
+// collapseSpaces - trim string and replace several spaces by one
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// unitDot - return position of dot separating mnemonic and unit, -1 if dot not found
+func (c *CurveDef) unitDot(s string) int {
+	first := strings.Index(s, ".")
+	if first < 0 {
+		return -1
+	}
+	tail := s[first+1:]
+	if strings.HasPrefix(tail, "[") {
+		return first
+	}
+	word := tail
+	if i := strings.IndexAny(tail, " ["); i >= 0 {
+		word = tail[:i]
+	}
+	last := strings.LastIndex(word, ".")
+	if (last < 0) || (last == len(word)-1) {
+		return first
+	}
+	if _, ok := StdUnits.Lookup(word); ok {
+		return first
+	}
+	c.warn("mnemonic '%s' contains dot, unit '%s' taken after last dot", s[:first+1+last], word[last+1:])
+	return first + 1 + last
+}
+
+// parseUnit - parse unit and API code, s - text after dot
+func (c *CurveDef) parseUnit(s string) {
+	if strings.HasPrefix(strings.TrimSpace(s), "[") {
+		s = strings.TrimSpace(s)
+		i := strings.Index(s, "]")
+		if i < 0 {
+			c.warn("unit '%s' has no closing bracket", s)
+			i = len(s)
+			s += "]"
+		}
+		c.Unit = strings.TrimSpace(s[1:i])
+		c.API = collapseSpaces(s[i+1:])
+		return
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return
+	}
+	if !strings.HasPrefix(s, " ") {
+		c.Unit = fields[0]
+		c.API = strings.Join(fields[1:], " ")
+		return
+	}
+	// space after dot: unit or API code
+	switch {
+	case (fields[0][0] >= '0') && (fields[0][0] <= '9'):
+		c.API = strings.Join(fields, " ")
+	case len(fields) == 1:
+		c.Unit = fields[0]
+	default:
+		c.Unit = fields[0]
+		c.API = strings.Join(fields[1:], " ")
+		c.warn("space after dot, '%s' taken as unit, '%s' as API code", c.Unit, c.API)
+	}
+}
+
+// unitField - return unit to write after dot, unit with spaces written in brackets
+func unitField(unit string) string {
+	if strings.Contains(unit, " ") {
+		return "[" + unit + "]"
+	}
+	return unit
+}
@@ -0,0 +1,160 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tParseCurveDef struct {
+	s    string
+	name string
+	unit string
+	api  string
+	desc string
+	warn int
+}
+
+var dParseCurveDef = []tParseCurveDef{
+	{"DEPT.M                      :  1  DEPTH", "DEPT", "M", "", "1 DEPTH", 0},
+	{"RHOB .K/M3        7 350 02 00:    2       BULK DENSITY", "RHOB", "K/M3", "7 350 02 00", "2 BULK DENSITY", 0},
+	{" DT     .US/M           60 520 32 00             :  2  SONIC", "DT", "US/M", "60 520 32 00", "2 SONIC", 0},
+	{" DT  .[US/M]     \t\t     :  2  SONIC TRANSIT TIME", "DT", "US/M", "", "2 SONIC TRANSIT TIME", 0},
+	{" TEMP.[DEG C] 7 350 : temperature", "TEMP", "DEG C", "7 350", "temperature", 0},
+	{" TEMP.[DEGC : temperature", "TEMP", "DEGC", "", "temperature", 1},
+	{"GR[0].gAPI: gamma ray image at angle 0 dega", "GR[0]", "gAPI", "", "gamma ray image at angle 0 dega", 0},
+	{"GR.1.gAPI  7 310 : gamma ray", "GR.1", "gAPI", "7 310", "gamma ray", 1},
+	{"RT.OHM.M : resistivity", "RT", "OHM.M", "", "resistivity", 0},
+	{"DEPT.M. : depth", "DEPT", "M.", "", "depth", 0},
+	{" .ILM .OHMM                   :  7  MEDIUM RESISTIVITY", "ILM", "OHMM", "", "7 MEDIUM RESISTIVITY", 1},
+	{" .OHMM                   :  5  RXO RESISTIVITY", "-EL-", "OHMM", "", "5 RXO RESISTIVITY", 1},
+	{" пс повт . мВ  : 7 кр сам", "пс повт", "мВ", "", "7 кр сам", 0},
+	{" ПС . мВ 7 350 : кр сам", "ПС", "мВ", "7 350", "кр сам", 1},
+	{" ПС . 7 350 : кр сам", "ПС", "", "7 350", "кр сам", 0},
+	{" Вторая запись   : ", "Вторая запись", "", "", "", 1},
+	{" DT  .US/M   ", "DT", "US/M", "", "", 0},
+	{" TIME.HH:MM  : time", "TIME", "HH:MM", "", "time", 0},
+}
+
+func TestParseCurveDef(t *testing.T) {
+	for i, tmp := range dParseCurveDef {
+		c := ParseCurveDef(tmp.s)
+		assert.Equal(t, tmp.name, c.Name, i)
+		assert.Equal(t, tmp.unit, c.Unit, i)
+		assert.Equal(t, tmp.api, c.API, i)
+		assert.Equal(t, tmp.desc, c.Desc, i)
+		assert.Equal(t, tmp.warn, len(c.Warnings), i, c.Warnings)
+	}
+}
+
+type tCurveSection struct {
+	fn    string
+	names string
+	units string
+	warn  string
+}
+
+var dCurveSection = []tCurveSection{
+	{"data/sample_bracketed_units.las", "DEPT DT RHOB NPHI SFLU SFLA ILM ILD", "M US/M K/M3 V/V OHMM OHMM OHMM OHMM", ""},
+	{"data/curve-section/mnemonic_leading_period.las", "DEPT DT RHOB NPHI SFLU SFLA ILM ILD", "M US/M K/M3 V/V OHMM OHMM OHMM OHMM", "begins with dot"},
+	{"data/curve-section/mnemonic_missing.las", "DEPT DT RHOB NPHI -EL- SFLA ILM ILD", "M US/M K/M3 V/V OHMM OHMM OHMM OHMM", "mnemonic missing"},
+	{"data/curve-section/mnemonic_duplicate.las", "DEPT DT RHOB NPHI SFLU SFLU:2 ILM ILD", "M US/M K/M3 V/V OHMM OHMM OHMM OHMM", "duplicate curve 'SFLU' renamed to 'SFLU:2'"},
+	{"data/curve-section/mnemonic_duplicate2.las", "DEPT DT RHOB NPHI RXO RES RES:2 RES:3", "M US/M K/M3 V/V OHMM OHMM OHMM OHMM", "duplicate curve 'RES' renamed to 'RES:3'"},
+	{"data/curve-section/sample_issue105_c.las", "DEPT GR GR:2 GR[0] GR[1] GR[2] GR[3] GR[4] GR[5]", "M gAPI gAPI gAPI gAPI gAPI gAPI gAPI gAPI", ""},
+}
+
+func TestCurveSection(t *testing.T) {
+	for _, tmp := range dCurveSection {
+		las := NewLas()
+		_, err := las.Open(tmp.fn)
+		assert.Nil(t, err, tmp.fn)
+		names := make([]string, 0, len(las.Logs))
+		units := make([]string, 0, len(las.Logs))
+		for _, c := range las.Logs {
+			names = append(names, c.Name)
+			units = append(units, c.Unit)
+		}
+		assert.Equal(t, tmp.names, strings.Join(names, " "), tmp.fn)
+		assert.Equal(t, tmp.units, strings.Join(units, " "), tmp.fn)
+		if len(tmp.warn) > 0 {
+			assert.Contains(t, las.Warnings.ToString(), tmp.warn, tmp.fn)
+		}
+	}
+}
+
+func TestCurveAPI(t *testing.T) {
+	las := NewLas()
+	las.Open("data/1.2/sample_curve_api.las")
+	assert.Equal(t, "", las.Logs[0].API)
+	assert.Equal(t, "7 350 02 00", las.Logs[1].API)
+	assert.Equal(t, "K/M3", las.Logs[1].Unit)
+	assert.Equal(t, "7 890 00 00", las.Logs[2].API)
+	assert.Equal(t, "VOL/VOL", las.Logs[2].Unit)
+	// API code saved and read back
+	b, _ := las.SaveToBuf(false)
+	assert.Contains(t, string(b), " RHOB.K/M3 7 350 02 00 ")
+	las2 := NewLas()
+	las2.Load(strings.NewReader(string(b)))
+	for i := range las.Logs {
+		assert.Equal(t, las.Logs[i].API, las2.Logs[i].API)
+		assert.Equal(t, las.Logs[i].Unit, las2.Logs[i].Unit)
+	}
+	las.SetSaveVersion(3.0, "COMMA")
+	b, _ = las.SaveToBuf(false)
+	las2 = NewLas()
+	las2.Load(strings.NewReader(string(b)))
+	assert.Equal(t, "7 350 02 00", las2.Logs[1].API)
+}
+
+func TestDuplicateNames(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 : version", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 2.0 :", "STEP.M 1.0 :", "NULL. -999.25 :",
+		"RUN. 1 : first run", "RUN. 2 : second run", "RUN. 3 :",
+		"~C", "DEPT.M :", "GR.API : 1", "GR.API : 2", "gr.API : 3", "GR.API : 4",
+		"~P", "BS.MM 200 :", "bs.MM 215 :",
+		"~A", "1.0 10 20 30 40", "2.0 11 21 31 41"}, "\n")
+	las := NewLas()
+	_, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	// first instance keeps name, next instances numbered in order of lines
+	names := make([]string, 0, len(las.Logs))
+	for _, c := range las.Logs {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, "DEPT GR GR:2 GR:3 GR:4", strings.Join(names, " ")) // names case insensitive
+	assert.Equal(t, 40.0, las.Logs[4].V[0])
+	assert.Equal(t, []string{"STRT", "STOP", "STEP", "NULL", "RUN", "RUN:2", "RUN:3"}, las.WelSec.Names())
+	assert.Equal(t, "2", las.WelSec.Value("RUN:2"))
+	assert.Equal(t, "3", las.WelSec.Value("RUN:3"))
+	assert.Equal(t, []string{"BS", "BS:2"}, las.ParSec.Names())
+	// lookup of all instances by name in source file
+	gr := las.Logs.ByIName("GR")
+	assert.Equal(t, 4, len(gr))
+	assert.Equal(t, "3", gr[2].Desc)
+	run := las.WelSec.ByIName("run")
+	assert.Equal(t, 3, len(run))
+	assert.Equal(t, "second run", run[1].Desc)
+	assert.Equal(t, 2, len(las.ParSec.ByIName("BS")))
+	assert.Equal(t, 0, len(las.Logs.ByIName("SP")))
+	// each rename reported
+	w := las.Warnings.ToString()
+	assert.Contains(t, w, "duplicate parameter 'RUN' renamed to 'RUN:2'")
+	assert.Contains(t, w, "duplicate parameter 'RUN' renamed to 'RUN:3'")
+	assert.Contains(t, w, "duplicate curve 'GR' renamed to 'GR:2'")
+	assert.Contains(t, w, "duplicate curve 'gr' renamed to 'GR:3'")
+	assert.Contains(t, w, "duplicate curve 'GR' renamed to 'GR:4'")
+	assert.Equal(t, 1, strings.Count(w, "'GR:2'"))
+	// renamed duplicates saved with name from source file, read back with the same names
+	b, _ := las.SaveToBuf(false)
+	assert.NotContains(t, string(b), " GR:2.")
+	las2 := NewLas()
+	las2.Load(strings.NewReader(string(b)))
+	for i := range las.Logs {
+		assert.Equal(t, las.Logs[i].Name, las2.Logs[i].Name)
+	}
+	assert.Equal(t, 3, len(las2.WelSec.ByIName("RUN")))
+	assert.Equal(t, "2", las2.WelSec.Value("RUN:2"))
+}
@@ -0,0 +1,83 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// errors of load
+
+package glasio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errors of load, returned wrapped in *LoadError, compare by errors.Is()
+var (
+	// ErrNilReader - Load() or LoadStream() received nil reader
+	ErrNilReader = errors.New("nil reader")
+	// ErrNilHandler - LoadStream() received nil handler
+	ErrNilHandler = errors.New("nil handler")
+	// ErrDecode - codepage of source not supported, source can not be decoded to UTF-8
+	ErrDecode = errors.New("source can not be decoded")
+	// ErrRead - error of reading source, data after error not read, also file not exist or can not be opened
+	ErrRead = errors.New("read error")
+	// ErrNoCurves - section ~C is empty or not exist, file ignored
+	ErrNoCurves = errors.New("curve section is empty")
+	// ErrStrict - violation of las 2.0 standard in strict mode, see Las.SetStrict(), description of violation in Cause
+	ErrStrict = errors.New("violation of las standard")
+)
+
+// LoadError - fatal error of load, use errors.As() to get file, line and section
+// Err is one of ErrNilReader, ErrDecode, ErrRead, ErrNoCurves, ErrStrict, errors.Is() compare with Err and Cause
+type LoadError struct {
+	File    string // name of file, "" if las loaded from reader
+	Line    int    // number of line from 1 where error detected, 0 if line unknown
+	Column  int    // number of character in line from 1, 0 if column unknown
+	Section string // ~V, ~W, ~C, ~A, "" if section unknown
+	Err     error  // kind of error
+	Cause   error  // error of reader or decoder, violation of standard, may be nil
+}
+
+func (e *LoadError) Error() string {
+	place := make([]string, 0, 4)
+	if len(e.File) > 0 {
+		place = append(place, fmt.Sprintf("file '%s'", e.File))
+	}
+	if e.Line > 0 {
+		place = append(place, fmt.Sprintf("line %d", e.Line))
+	}
+	if e.Column > 0 {
+		place = append(place, fmt.Sprintf("column %d", e.Column))
+	}
+	if len(e.Section) > 0 {
+		place = append(place, "section "+e.Section)
+	}
+	msg := e.Err.Error()
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	if len(place) == 0 {
+		return msg
+	}
+	return strings.Join(place, ", ") + ": " + msg
+}
+
+// Unwrap - return kind of error
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Is - errors.Is() match cause also: ErrRead and fs.ErrNotExist
+func (e *LoadError) Is(target error) bool {
+	return (e.Cause != nil) && errors.Is(e.Cause, target)
+}
+
+// As - errors.As() find in cause also: *fs.PathError
+func (e *LoadError) As(target interface{}) bool {
+	return (e.Cause != nil) && errors.As(e.Cause, target)
+}
+
+// loadError - create error of load with name of file and current line
+func (las *Las) loadError(err error, section int, cause error) *LoadError {
+	w := TWarning{Section: section}
+	return &LoadError{las.FileName, las.currentLine, 0, w.SectionName(), err, cause}
+}
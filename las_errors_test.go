@@ -0,0 +1,69 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadErrors(t *testing.T) {
+	las := NewLas()
+	_, err := las.Load(nil)
+	assert.True(t, errors.Is(err, ErrNilReader))
+	_, err = las.LoadStream(strings.NewReader(""), nil)
+	assert.True(t, errors.Is(err, ErrNilHandler))
+
+	// file not exist: read error with name of file and cause
+	for _, open := range []func(string) (int, error){
+		NewLas().Open,
+		func(fn string) (int, error) { return NewLas().OpenStream(fn, func(row *LasRow) error { return nil }) },
+	} {
+		_, err = open("not_exist_file.las")
+		var e *LoadError
+		if assert.True(t, errors.As(err, &e)) {
+			assert.Equal(t, "not_exist_file.las", e.File)
+			assert.True(t, errors.Is(err, ErrRead))
+			assert.True(t, errors.Is(err, fs.ErrNotExist))
+			var pe *fs.PathError
+			assert.True(t, errors.As(err, &pe))
+			assert.False(t, errors.Is(err, ErrDecode))
+		}
+	}
+
+	// section ~C empty: file, line and section of error
+	las = NewLas()
+	_, err = las.Open("data/barebones2.las")
+	if assert.NotNil(t, err) {
+		assert.True(t, errors.Is(err, ErrNoCurves), err.Error())
+		var e *LoadError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "data/barebones2.las", e.File)
+		assert.Equal(t, "~C", e.Section)
+		assert.Nil(t, e.Cause)
+	}
+	src := strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1 :", "STOP.M 2 :", "STEP.M 1 :", "NULL. -999.25 :", "~C", "~A", "1 2"}, "\n")
+	las = NewLas()
+	_, err = las.Load(strings.NewReader(src))
+	var e *LoadError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, "", e.File)
+		assert.Equal(t, 10, e.Line)
+		assert.Equal(t, "line 10, section ~C: curve section is empty", e.Error())
+	}
+	_, err = NewLas().LoadStream(strings.NewReader(src), func(row *LasRow) error { return nil })
+	assert.True(t, errors.Is(err, ErrNoCurves))
+
+	// line longer than buffer of scanner: read error with line
+	src = strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~C", "DEPT.M :", "~A", "1", strings.Repeat("1", 70000)}, "\n")
+	_, err = NewLas().Load(strings.NewReader(src))
+	if assert.True(t, errors.As(err, &e)) {
+		assert.True(t, errors.Is(err, ErrRead))
+		assert.Equal(t, 8, e.Line)
+		assert.NotNil(t, e.Cause)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzLoad - any input loaded without panic by all load paths
+// corpus: files of data/, inputs failed on fuzzing stored to testdata/fuzz/FuzzLoad
+// go test -fuzz FuzzLoad
+func FuzzLoad(f *testing.F) {
+	filepath.Walk("data", func(path string, info os.FileInfo, err error) error {
+		if (err == nil) && !info.IsDir() && (filepath.Ext(path) == ".las") {
+			if b, err := ioutil.ReadFile(path); err == nil {
+				f.Add(b)
+			}
+		}
+		return nil
+	})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		loadAll(b)
+	})
+}
@@ -0,0 +1,353 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// step of index and type of sampling
+
+package glasio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IndexKind - type of index sampling
+type IndexKind int
+
+const (
+	// IndexUnknown - index not contain two different values, step cannot be determined
+	IndexUnknown IndexKind = iota
+	// IndexRegular - all differences between neighboring index values equal to step
+	IndexRegular
+	// IndexRegularGaps - differences equal to step or multiple of step, some depths are missing
+	IndexRegularGaps
+	// IndexIrregular - index has not constant step, by LAS 2.0 STEP must be 0
+	IndexIrregular
+)
+
+func (k IndexKind) String() string {
+	switch k {
+	case IndexRegular:
+		return "regular"
+	case IndexRegularGaps:
+		return "regular with gaps"
+	case IndexIrregular:
+		return "irregular"
+	}
+	return "unknown"
+}
+
+// StepTolerance - relative tolerance on compare difference of index values with step
+// values in data section are written with limited precision, for step 1/12 ft written as 0.0833 error is about 0.1%
+var StepTolerance = 0.01
+
+// IndexStep - determine step of index and type of sampling
+// duplicated values are ignored, step is median of differences between neighboring values,
+// then step refined as sum of differences divided by number of steps in them, differences not multiple of step are excluded
+// for irregular index step estimated by differences multiple of step, by LAS 2.0 such file must have STEP = 0
+// if index contain less than two different values returns 0, IndexUnknown
+func IndexStep(index []float64) (float64, IndexKind) {
+	diff := make([]float64, 0, len(index))
+	for i := 1; i < len(index); i++ {
+		if d := index[i] - index[i-1]; d != 0 {
+			diff = append(diff, d)
+		}
+	}
+	if len(diff) == 0 {
+		return 0, IndexUnknown
+	}
+	st := newStepStat(diff)
+	for _, d := range diff {
+		st.add(d)
+	}
+	return st.result()
+}
+
+// stepStat - running estimation of index step, differences classified by base step
+type stepStat struct {
+	base float64   // step determined as median of differences
+	kind IndexKind // regular, gaps or irregular
+	sum  float64   // sum of differences multiple of base step
+	n    float64   // number of base steps in sum
+}
+
+// newStepStat - base step is median of differences diff, diff must not be empty and must not contain 0
+func newStepStat(diff []float64) stepStat {
+	sorted := append([]float64(nil), diff...)
+	sort.Float64s(sorted)
+	step := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		step = (sorted[len(sorted)/2-1] + step) / 2
+	}
+	return stepStat{base: step, kind: IndexRegular}
+}
+
+// add - classify difference d of neighboring values, d must not be 0
+func (st *stepStat) add(d float64) {
+	tol := math.Abs(st.base) * StepTolerance
+	k := math.Round(d / st.base)
+	switch {
+	case math.Abs(d-st.base) <= tol:
+	case (k >= 2) && (math.Abs(d-k*st.base) <= k*tol):
+		if st.kind == IndexRegular {
+			st.kind = IndexRegularGaps
+		}
+	default:
+		st.kind = IndexIrregular
+		return
+	}
+	st.sum += d
+	st.n += k
+}
+
+// result - step refined as sum of differences divided by number of steps in them
+func (st *stepStat) result() (float64, IndexKind) {
+	step := st.base
+	if st.n > 0 {
+		step = st.sum / st.n
+	}
+	return roundStep(step), st.kind
+}
+
+// roundStep - remove noise of float arithmetic, step is rounded to 6 significant digits
+func roundStep(step float64) float64 {
+	if step == 0 {
+		return 0
+	}
+	p := math.Pow(10, 5-math.Floor(math.Log10(math.Abs(step))))
+	return math.Round(step*p) / p
+}
+
+// IndexKind - return type of index sampling
+// if data loaded index taken from first curve, else from lines of data section read to rows
+func (las *Las) IndexKind() IndexKind {
+	var index []float64
+	if las.NumPoints() > 0 {
+		index = las.Logs[0].D
+	} else {
+		index = las.indexFromData(-1)
+	}
+	_, kind := IndexStep(index)
+	return kind
+}
+
+// IndexType - type of values of index (first curve)
+type IndexType int
+
+const (
+	// IndexDepth - index is depth: DEPT, DEPTH, MD, TVD ...
+	IndexDepth IndexType = iota
+	// IndexTime - index is time in units of index curve: TIME.S, ETIM.MIN ...
+	IndexTime
+	// IndexDateTime - index is date and time in ISO 8601 format, values stored as seconds from 1970-01-01 UTC
+	IndexDateTime
+)
+
+func (t IndexType) String() string {
+	switch t {
+	case IndexTime:
+		return "time"
+	case IndexDateTime:
+		return "date-time"
+	}
+	return "depth"
+}
+
+// timeIndexNames - mnemonics of time index curve
+var timeIndexNames = map[string]bool{"TIME": true, "ETIM": true, "ETIME": true, "ELTIM": true, "TIM": true}
+
+// dateTimeIndexNames - mnemonics of date-time index curve
+var dateTimeIndexNames = map[string]bool{"DATE": true, "DATETIME": true, "TIMESTAMP": true}
+
+// dateTimeLayouts - layouts of date-time values of index
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04", "2006-01-02"}
+
+// IndexType - return type of index, determined by name and unit of first curve
+// if name or unit not recognized, index is date-time when STRT contains date-time value
+func (las *Las) IndexType() IndexType {
+	if len(las.Logs) == 0 {
+		return IndexDepth
+	}
+	name := strings.ToUpper(las.Logs[0].Name)
+	unit := strings.ToUpper(las.Logs[0].Unit)
+	switch {
+	case dateTimeIndexNames[name], strings.Contains(unit, "YY"), unit == "ISO", unit == "DATETIME":
+		return IndexDateTime
+	case timeIndexNames[name]:
+		if _, err := strconv.ParseFloat(las.parStr(las.WelSec, "STRT", ""), 64); err != nil {
+			if _, err := parseDateTime(las.parStr(las.WelSec, "STRT", "")); err == nil {
+				return IndexDateTime
+			}
+		}
+		return IndexTime
+	default:
+		if _, ok := StdUnits.lookupDim(unit, DimTime); ok {
+			return IndexTime
+		}
+	}
+	return IndexDepth
+}
+
+// parseDateTime - convert date-time value from ISO 8601 format, without time zone time is UTC
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not date-time", s)
+}
+
+// parseIndex - convert value of index to number
+// numbers are depth or time, date-time value converted to seconds from 1970-01-01 UTC
+func parseIndex(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return v, nil
+	}
+	t, err := parseDateTime(s)
+	if err != nil {
+		return 0, errors.New("index value '" + s + "' not numeric and not date-time")
+	}
+	return float64(t.UnixNano()) / 1e9, nil
+}
+
+// parIndex - return value of parameter with index value: STRT, STOP
+func (las *Las) parIndex(sec HeaderSection, name string, defValue float64) float64 {
+	v := defValue
+	if p, ok := sec.params[name]; ok {
+		v, _ = parseIndex(p.Val)
+	}
+	return v
+}
+
+// formatIndex - return index value as string, date-time index in ISO 8601 format
+// format - format of numeric value, "" - shortest representation without loss of precision
+func (las *Las) formatIndex(v float64, format string) string {
+	if (las.IndexType() == IndexDateTime) && (v != las.NULL()) {
+		return secondsToTime(v).Format("2006-01-02T15:04:05.999999999")
+	}
+	if len(format) == 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf(format, v)
+}
+
+// IndexUnit - return unit of index: unit of first curve, if it empty unit of STRT, by default M
+// date-time index may have no unit
+func (las *Las) IndexUnit() string {
+	if (len(las.Logs) > 0) && (len(las.Logs[0].Unit) > 0) {
+		return las.Logs[0].Unit
+	}
+	if p, ok := las.WelSec.params["STRT"]; ok && (len(p.Unit) > 0) {
+		return p.Unit
+	}
+	if las.IndexType() == IndexDateTime {
+		return ""
+	}
+	return "M"
+}
+
+// indexCaption - return name of index for descriptions in section ~W
+func (las *Las) indexCaption() string {
+	if las.IndexType() == IndexDepth {
+		return "DEPTH"
+	}
+	return "TIME"
+}
+
+func secondsToTime(v float64) time.Time {
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC()
+}
+
+// IndexTime - return values of date-time index as time.Time
+// for depth or time index return nil
+func (las *Las) IndexTime() []time.Time {
+	if las.IndexType() != IndexDateTime {
+		return nil
+	}
+	res := make([]time.Time, las.NumPoints())
+	for i, v := range las.Dept() {
+		res[i] = secondsToTime(v)
+	}
+	return res
+}
+
+// IndexSeconds - return values of time or date-time index in seconds
+// time index converted by unit of index curve: S, MS, MIN, H, D, if unit unknown values are seconds
+// for date-time index return seconds from 1970-01-01 UTC, for depth index return nil
+func (las *Las) IndexSeconds() []float64 {
+	k := 1.0
+	switch las.IndexType() {
+	case IndexDepth:
+		return nil
+	case IndexTime:
+		if u, ok := StdUnits.lookupDim(las.Logs[0].Unit, DimTime); ok {
+			k = u.Factor
+		}
+	}
+	res := make([]float64, las.NumPoints())
+	for i, v := range las.Dept() {
+		res[i] = v * k
+	}
+	return res
+}
+
+// DepthUnitFactor - return length of depth unit in metres, false if unit is not depth unit
+// known units: M, F, FT, .1IN, IN, CM, MM, see StdUnits
+func DepthUnitFactor(unit string) (float64, bool) {
+	u, ok := StdUnits.lookupDim(unit, DimLength)
+	return u.Factor, ok
+}
+
+// ConvertIndex - convert depth index to unit: M, F, FT, .1IN ...
+// values of index and STRT, STOP, STEP are rescaled, unit of first curve and STRT, STOP, STEP set to unit
+// data groups of las 3.0 not converted
+func (las *Las) ConvertIndex(unit string) error {
+	if las.IndexType() != IndexDepth {
+		return fmt.Errorf("index is %s, convert to '%s' impossible", las.IndexType(), unit)
+	}
+	from, ok := DepthUnitFactor(las.IndexUnit())
+	if !ok {
+		return fmt.Errorf("unknown unit of index '%s'", las.IndexUnit())
+	}
+	to, ok := DepthUnitFactor(unit)
+	if !ok {
+		return fmt.Errorf("unknown depth unit '%s'", unit)
+	}
+	k := from / to
+	for j := range las.Logs {
+		for i := range las.Logs[j].D {
+			las.Logs[j].D[i] = roundDepth(las.Logs[j].D[i] * k)
+		}
+	}
+	if len(las.Logs) > 0 {
+		for i, v := range las.Logs[0].V {
+			if v != las.NULL() {
+				las.Logs[0].V[i] = roundDepth(v * k)
+			}
+		}
+		las.Logs[0].Unit = unit
+	}
+	for _, name := range []string{"STRT", "STOP", "STEP"} {
+		p, ok := las.WelSec.params[name]
+		if !ok {
+			continue
+		}
+		if v, err := strconv.ParseFloat(p.Val, 64); (err == nil) && (v != las.NULL()) {
+			p.Val = strconv.FormatFloat(roundDepth(v*k), 'f', -1, 64)
+		}
+		p.Unit = unit
+		las.WelSec.params[name] = p
+	}
+	return nil
+}
+
+// roundDepth - remove noise of float arithmetic after convert, depth rounded to 1e-9
+func roundDepth(v float64) float64 {
+	return math.Round(v*1e9) / 1e9
+}
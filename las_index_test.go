@@ -0,0 +1,193 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tIndexStep struct {
+	index []float64
+	step  float64
+	kind  IndexKind
+}
+
+var dIndexStep = []tIndexStep{
+	{[]float64{}, 0, IndexUnknown},
+	{[]float64{1}, 0, IndexUnknown},
+	{[]float64{1, 1, 1}, 0, IndexUnknown},
+	{[]float64{1, 2, 3, 4}, 1, IndexRegular},
+	{[]float64{1, 1, 1.1, 1.2, 1.3}, 0.1, IndexRegular},                  // first row duplicated
+	{[]float64{10, 9.9, 9.8, 9.7}, -0.1, IndexRegular},                   // decreasing index
+	{[]float64{0, 0.0254, 0.0508, 0.0762, 0.1016}, 0.0254, IndexRegular}, // 1 inch in metres
+	{[]float64{100, 100.0833, 100.1667, 100.25, 100.3333, 100.4167, 100.5, 100.5833, 100.6667, 100.75, 100.8333, 100.9167, 101}, 0.0833333, IndexRegular}, // 1/12 ft written with 4 digits
+	{[]float64{1, 2, 3, 6, 7, 8}, 1, IndexRegularGaps},
+	{[]float64{1, 1.5, 2, 3.5, 4}, 0.5, IndexRegularGaps},
+	{[]float64{1, 2, 3, 3.5, 4.7, 8}, 1, IndexIrregular},
+	{[]float64{1, 2, 3, 2, 3, 4}, 1, IndexIrregular}, // not monotony
+}
+
+func TestIndexStep(t *testing.T) {
+	for i, tmp := range dIndexStep {
+		step, kind := IndexStep(tmp.index)
+		assert.Equal(t, tmp.kind, kind, fmt.Sprintf("test %d, index %v", i, tmp.index))
+		if kind != IndexIrregular {
+			assert.Equal(t, tmp.step, step, fmt.Sprintf("test %d, index %v", i, tmp.index))
+		}
+	}
+}
+
+func TestIndexKind(t *testing.T) {
+	las := NewLas()
+	las.Load(strings.NewReader(strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 4.0 :", "STEP.M 0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0 1", "2.0 2", "2.5 3", "4.0 4"}, "\n")))
+	assert.Equal(t, IndexIrregular, las.IndexKind())
+	assert.Equal(t, 0.0, las.STEP())
+	assert.Contains(t, las.Warnings.ToString(), "index is irregular")
+
+	las = NewLas()
+	las.Load(strings.NewReader(strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 4.0 :", "STEP.M 0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0 1", "1.0 1", "1.5 2", "3.0 3", "3.5 4"}, "\n")))
+	assert.Equal(t, IndexRegularGaps, las.IndexKind())
+	assert.Equal(t, 0.5, las.STEP())
+	assert.Equal(t, "regular with gaps", las.IndexKind().String())
+}
+
+// STRT, STOP, STEP saved without rounding, reloaded file has same step
+func TestIndexStepSave(t *testing.T) {
+	las := NewLas()
+	las.Load(strings.NewReader(strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 1.0508 :", "STEP.M 0.0254 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0 1", "1.0254 2", "1.0508 3"}, "\n")))
+	assert.Equal(t, 0.0254, las.STEP())
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), " STEP.M   0.0254 ")
+	assert.Contains(t, string(b), " STOP.M   1.0508 ")
+
+	las2 := NewLas()
+	_, err = las2.Load(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0254, las2.STEP())
+	assert.Equal(t, 1.0508, las2.STOP())
+	assert.NotContains(t, las2.Warnings.ToString(), "STEP: ")
+}
+
+type tIndexType struct {
+	fn      string
+	typ     IndexType
+	strt    float64
+	step    float64
+	seconds []float64
+}
+
+var dIndexType = []tIndexType{
+	{"data/2.0/sample_2.0.las", IndexDepth, 1670, -0.125, nil},
+	{"test_files/sample_time.las", IndexTime, 0, 0.5, []float64{0, 30, 60, 90, 120}},
+	{"test_files/sample_datetime.las", IndexDateTime, 1591005600, 60, []float64{1591005600, 1591005660, 1591005720, 1591005780}},
+}
+
+func TestIndexType(t *testing.T) {
+	for _, tmp := range dIndexType {
+		las := NewLas()
+		_, err := las.Open(tmp.fn)
+		assert.Nil(t, err, tmp.fn)
+		assert.Equal(t, tmp.typ, las.IndexType(), tmp.fn)
+		assert.Equal(t, tmp.strt, las.STRT(), tmp.fn)
+		assert.Equal(t, tmp.step, las.STEP(), tmp.fn)
+		assert.Equal(t, tmp.seconds, las.IndexSeconds(), tmp.fn)
+		// index type, unit and values saved and read back
+		b, err := las.SaveToBuf(false)
+		assert.Nil(t, err, tmp.fn)
+		las2 := NewLas()
+		_, err = las2.Load(bytes.NewReader(b))
+		assert.Nil(t, err, tmp.fn)
+		assert.Equal(t, tmp.typ, las2.IndexType(), tmp.fn)
+		assert.Equal(t, las.STRT(), las2.STRT(), tmp.fn)
+		assert.Equal(t, las.STOP(), las2.STOP(), tmp.fn)
+		assert.Equal(t, las.Dept(), las2.Dept(), tmp.fn)
+	}
+}
+
+func TestIndexTimeSave(t *testing.T) {
+	las := NewLas()
+	las.Open("test_files/sample_time.las")
+	b, _ := las.SaveToBuf(false)
+	assert.Contains(t, string(b), " STRT.MIN        0 ")
+	assert.Contains(t, string(b), ":START TIME")
+	assert.Contains(t, string(b), " ETIM.MIN ")
+
+	las = NewLas()
+	las.Open("test_files/sample_datetime.las")
+	assert.Equal(t, time.Date(2020, 6, 1, 10, 2, 0, 0, time.UTC), las.IndexTime()[2])
+	b, _ = las.SaveToBuf(false)
+	assert.Contains(t, string(b), " STRT. 2020-06-01T10:00:00 ")
+	assert.Contains(t, string(b), " STEP.S       60 ")
+	assert.Contains(t, string(b), "\n2020-06-01T10:01:00 ")
+
+	las = NewLas()
+	las.Open("data/2.0/sample_2.0.las")
+	assert.Nil(t, las.IndexTime())
+	b, _ = las.SaveToBuf(false)
+	assert.Contains(t, string(b), " STRT.M     1670 ")
+	assert.Contains(t, string(b), ":START DEPTH")
+}
+
+func TestConvertIndex(t *testing.T) {
+	las := NewLas()
+	las.Open("data/autodepthindex_F.las")
+	assert.Equal(t, "F", las.IndexUnit())
+	b, _ := las.SaveToBuf(false)
+	assert.Contains(t, string(b), " STOP.F      100 ")
+	assert.Contains(t, string(b), " DEPT.F ")
+
+	assert.Nil(t, las.ConvertIndex("M"))
+	assert.Equal(t, "M", las.IndexUnit())
+	assert.Equal(t, 30.48, las.STOP())
+	assert.Equal(t, 0.3048, las.STEP())
+	assert.Equal(t, 0.9144, las.Dept()[3])
+	assert.Equal(t, 0.9144, las.Logs[1].D[3])
+	assert.Equal(t, 0.9144, las.Logs[0].V[3])
+	assert.Equal(t, "M", las.WelSec.params["STRT"].Unit)
+	b, _ = las.SaveToBuf(false)
+	assert.Contains(t, string(b), " STOP.M    30.48 ")
+
+	assert.Nil(t, las.ConvertIndex(".1IN"))
+	assert.Equal(t, 12000.0, las.STOP())
+	assert.Nil(t, las.ConvertIndex("FT"))
+	assert.Equal(t, 100.0, las.STOP())
+	assert.Equal(t, 3.0, las.Dept()[3])
+
+	assert.NotNil(t, las.ConvertIndex("SEC"))
+	las = NewLas()
+	las.Open("test_files/sample_time.las")
+	assert.NotNil(t, las.ConvertIndex("M"))
+}
+
+// converted STRT, STOP, STEP keep precision after save and reload
+func TestConvertIndexSave(t *testing.T) {
+	las := NewLas()
+	las.Open("data/autodepthindex_F.las")
+	assert.Nil(t, las.ConvertIndex("M"))
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), " STEP.M   0.3048 ")
+
+	las2 := NewLas()
+	_, err = las2.Load(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, "M", las2.IndexUnit())
+	assert.Equal(t, 0.0, las2.STRT())
+	assert.Equal(t, 30.48, las2.STOP())
+	assert.Equal(t, 0.3048, las2.STEP())
+	assert.Equal(t, 0.9144, las2.Dept()[3])
+	assert.NotContains(t, las2.Warnings.ToString(), "STEP: ")
+}
@@ -51,10 +51,6 @@ func (m *tCheckMsg) save(f *os.File) {
 	}
 }
 
-func (m *tCheckMsg) msgFileIsWraped(fn string) string {
-	return fmt.Sprintf("file '%s' ignore, WRAP=YES\n", fn)
-}
-
 func (m *tCheckMsg) msgFileNoData(fn string) string {
 	return fmt.Sprintf("*error* file '%s', no data read ,*ignore*\n", fn)
 }
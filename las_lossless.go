@@ -0,0 +1,311 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// lossless save: unchanged lines of source file written as is
+
+package glasio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// lineRange - numbers of first and last line of one record of data section, numbers as in HeaderParam.lineNo (from 1)
+type lineRange struct {
+	first, last int
+}
+
+// headerItem - parameter or curve of header as it was read, used to find changed lines on lossless save
+type headerItem struct {
+	key string // section: V, W, P, O, C - curves, for groups of las 3.0: name of group + "_P" or "_C"
+	HeaderParam
+	api    string // API code of curve
+	format string // format specifier of curve
+}
+
+// SetLossless - set lossless mode of save
+// in lossless mode las loaded by Load() or Open() saved with original formatting and comments,
+// only lines of changed parameters, curves and data records are rewritten,
+// new parameters written after last line of section, new data records after last line of data section
+// codepage, delimiters and line ending of source file are kept, SetSaveVersion(), SetWrapWidth() and codepage of NewLas() ignored
+// values repaired on load (STRT, STEP, NULL) are changes, their lines rewritten
+func (las *Las) SetLossless(on bool) {
+	las.lossless = on
+}
+
+// scanLines - split function for scanner, the same as bufio.ScanLines, also store line ending of source
+func (las *Las) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if advance > 0 {
+		eol := string(data[len(token):advance])
+		if (len(las.eol) == 0) && (len(eol) > 0) {
+			las.eol = eol
+		}
+		las.lastEOL = len(eol) > 0
+	}
+	return advance, token, err
+}
+
+// newScanner - create scanner of las.Reader, line endings of source stored
+func (las *Las) newScanner() *bufio.Scanner {
+	scanner := bufio.NewScanner(las.Reader)
+	scanner.Split(las.scanLines)
+	return scanner
+}
+
+// headerItems - return all parameters and curves of header, section ~C presented by curves
+func (las *Las) headerItems() []headerItem {
+	items := make([]headerItem, 0, len(las.rows))
+	addSec := func(key string, sec HeaderSection) {
+		for _, p := range sec.params {
+			items = append(items, headerItem{key, p, "", ""})
+		}
+	}
+	addCurves := func(key string, curves LasCurves) {
+		for _, c := range curves {
+			items = append(items, headerItem{key, c.HeaderParam, c.API, c.Format})
+		}
+	}
+	addSec("V", las.VerSec)
+	addSec("W", las.WelSec)
+	addSec("P", las.ParSec)
+	addSec("O", las.OthSec)
+	addCurves("C", las.Logs)
+	for _, g := range las.Groups {
+		addSec(g.Name+"_P", g.ParSec)
+		addCurves(g.Name+"_C", g.Curves)
+	}
+	return items
+}
+
+// storeHeader - store parameters and curves as they was read, called after load of header
+func (las *Las) storeHeader() {
+	las.header = make(map[int]headerItem)
+	for _, it := range las.headerItems() {
+		if it.lineNo > 0 {
+			las.header[it.lineNo] = it
+		}
+	}
+}
+
+// formatItem - return line of header for changed or new parameter
+func (las *Las) formatItem(it headerItem) string {
+	switch {
+	case it.key == "O":
+		return it.Val
+	case (it.key == "W") && (las.VERS() < 2.0) && it.valueAfterColon():
+		return strings.TrimSuffix(fmt.Sprintf(_LasParamLine, it.saveName(), it.Unit, it.Desc, it.Val), "\n")
+	case strings.HasSuffix(it.key, "C") && (las.VERS() >= 3.0):
+		f := it.format
+		if len(f) == 0 {
+			f = "F"
+		}
+		return strings.TrimSuffix(fmt.Sprintf(_LasParamLine30, it.saveName(), unitField(it.Unit), it.api, it.Desc, f), "\n")
+	case strings.HasSuffix(it.key, "C"):
+		return strings.TrimSuffix(fmt.Sprintf(_LasParamLine, it.saveName(), unitField(it.Unit), it.api, it.Desc), "\n")
+	}
+	return strings.TrimSuffix(fmt.Sprintf(_LasParamLine, it.saveName(), it.Unit, it.Val, it.Desc), "\n")
+}
+
+// losslessEdit - changes of source lines: replaced lines, removed lines, lines inserted after line
+type losslessEdit struct {
+	replace map[int][]string
+	skip    map[int]bool
+	insert  map[int][]string
+}
+
+// saveLossless - write source lines with changes
+func (las *Las) saveLossless(b *bytes.Buffer) {
+	e := losslessEdit{make(map[int][]string), make(map[int]bool), make(map[int][]string)}
+	las.editHeader(&e)
+	records := las.pointLines
+	if las.recordLines != nil { // points reordered by repairs, records written in place of source records by order of file
+		records = las.recordLines
+	}
+	las.editData(&e, las.Logs, las.headerCount("C"), records, las.dataStart, true)
+	for _, g := range las.Groups {
+		las.editData(&e, g.Curves, las.headerCount(g.Name+"_C"), g.lines, 0, false)
+	}
+	lines := make([]string, 0, len(las.rows))
+	for i, s := range las.rows {
+		n := i + 1
+		if r, ok := e.replace[n]; ok {
+			lines = append(lines, r...)
+		} else if !e.skip[n] {
+			lines = append(lines, s)
+		}
+		lines = append(lines, e.insert[n]...)
+	}
+	eol := las.eol
+	if len(eol) == 0 {
+		eol = "\n"
+	}
+	b.WriteString(strings.Join(lines, eol))
+	if las.lastEOL && (len(lines) > 0) {
+		b.WriteString(eol)
+	}
+}
+
+// editHeader - find changed, removed and new parameters and curves
+func (las *Las) editHeader(e *losslessEdit) {
+	last := make(map[string]int) // last line of section
+	for n, it := range las.header {
+		if n > last[it.key] {
+			last[it.key] = n
+		}
+	}
+	present := make(map[int]bool)
+	added := make(map[string][]headerItem)
+	for _, it := range las.headerItems() {
+		old, ok := las.header[it.lineNo]
+		if !ok || (old.key != it.key) {
+			added[it.key] = append(added[it.key], it)
+			continue
+		}
+		present[it.lineNo] = true
+		if old != it {
+			e.replace[it.lineNo] = []string{las.formatItem(it)}
+		}
+	}
+	for n := range las.header {
+		if !present[n] {
+			e.skip[n] = true
+		}
+	}
+	for _, key := range []string{"V", "W", "C", "P", "O"} {
+		items := added[key]
+		if len(items) == 0 {
+			continue
+		}
+		sortItems(items)
+		after := last[key]
+		lines := make([]string, 0, len(items)+1)
+		if after == 0 { // section not exist in source, placed before data section
+			after = las.dataStart - 1
+			switch key {
+			case "P":
+				lines = append(lines, strings.TrimSuffix(_LasParamSec, "\n"))
+			case "O":
+				lines = append(lines, strings.TrimSuffix(_LasOtherSec, "\n"))
+			}
+		}
+		for _, it := range items {
+			lines = append(lines, las.formatItem(it))
+		}
+		e.insert[after] = append(e.insert[after], lines...)
+	}
+	for _, g := range las.Groups {
+		for _, key := range []string{g.Name + "_P", g.Name + "_C"} {
+			items := added[key]
+			if (len(items) == 0) || (last[key] == 0) {
+				continue // new groups not saved in lossless mode
+			}
+			sortItems(items)
+			for _, it := range items {
+				e.insert[last[key]] = append(e.insert[last[key]], las.formatItem(it))
+			}
+		}
+	}
+}
+
+// sortItems - sort new parameters in order of section
+func sortItems(items []headerItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return paramLess(items[i].HeaderParam, items[j].HeaderParam)
+	})
+}
+
+// headerCount - return number of source parameters or curves of section
+func (las *Las) headerCount(key string) int {
+	n := 0
+	for _, it := range las.header {
+		if it.key == key {
+			n++
+		}
+	}
+	return n
+}
+
+// editData - find changed, removed and new records of data section
+// ncol - number of curves in source, if curves added or removed all records rewritten
+// lines - source lines of records, titleLine - number of line before first line of data section, used if source has no records
+func (las *Las) editData(e *losslessEdit, curves LasCurves, ncol int, lines []lineRange, titleLine int, main bool) {
+	if len(curves) == 0 {
+		return
+	}
+	np := len(curves[0].D)
+	for i, r := range lines {
+		if (i < np) && (len(curves) == ncol) && las.recordEqual(curves, i, r, main) {
+			continue
+		}
+		for n := r.first; n <= r.last; n++ {
+			e.skip[n] = true
+		}
+		if i < np {
+			e.replace[r.first] = las.formatRecord(curves, i, main)
+		}
+	}
+	if np <= len(lines) {
+		return
+	}
+	after := titleLine
+	if len(lines) > 0 {
+		after = lines[len(lines)-1].last
+	}
+	for i := len(lines); i < np; i++ {
+		e.insert[after] = append(e.insert[after], las.formatRecord(curves, i, main)...)
+	}
+}
+
+// recordEqual - return true if values of record i equal to values of source lines
+func (las *Las) recordEqual(curves LasCurves, i int, r lineRange, main bool) bool {
+	fields := make([]string, 0, len(curves))
+	for n := r.first; (n <= r.last) && (n <= len(las.rows)); n++ {
+		fields = append(fields, splitDataLine(strings.TrimSpace(las.rows[n-1]), las.DLM())...)
+	}
+	for j := range curves {
+		c := &curves[j]
+		s := ""
+		if j < len(fields) {
+			s = fields[j]
+		}
+		if c.IsString() {
+			if (i >= len(c.S)) || (c.S[i] != s) {
+				return false
+			}
+			continue
+		}
+		v := las.NULL()
+		if j < len(fields) {
+			if main && (j == 0) {
+				v, _ = parseIndex(s)
+			} else {
+				v, _ = las.parseDataValue(s)
+			}
+		}
+		if (c.V[i] != v) || (main && (j == 0) && (c.D[i] != v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatRecord - return lines of changed or new record, format depends on version and WRAP of source
+func (las *Las) formatRecord(curves LasCurves, i int, main bool) []string {
+	var b bytes.Buffer
+	switch {
+	case las.VERS() >= 3.0:
+		las.saveRecord30(&b, curves, formats30(curves), i, las.DLM())
+	case las.IsWraped():
+		width := las.oWrapWidth
+		if width <= 0 {
+			width = 80
+		}
+		las.saveWrapRecord(&b, i, width)
+	default:
+		las.saveRecord(&b, i)
+	}
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
@@ -0,0 +1,135 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/softlandia/cpd"
+	"github.com/stretchr/testify/assert"
+)
+
+var dLosslessFiles = []string{
+	"data/2.0/sample_2.0.las",
+	"data/2.0/sample_2.0_wrapped.las",
+	"data/1.2/sample.las",
+	"data/1.2/sample_wrapped.las",
+	"data/1.2/sample_curve_api.las",
+	"data/sample_bracketed_units.las",
+	"data/tabulated_data.las",
+	"data/curve-section/sparse_curves.las",
+	"test_files/sample_3.0.las",
+	"test_files/sample_datetime.las",
+	"test_files/~866.las",
+	"test_files/~1251.las",
+	"test_files/~koi8.las",
+}
+
+func TestLosslessRoundTrip(t *testing.T) {
+	for _, fn := range dLosslessFiles {
+		src, err := ioutil.ReadFile(fn)
+		assert.Nil(t, err, fn)
+		las := NewLas()
+		las.SetLossless(true)
+		_, err = las.Open(fn)
+		assert.Nil(t, err, fn)
+		b, err := las.SaveToBuf(false)
+		assert.Nil(t, err, fn)
+		assert.Equal(t, string(src), string(b), fn)
+	}
+	// CRLF line ending, no line ending at end of file
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 : version", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0   10", "2.0   20", "3.0   30"}, "\r\n")
+	las := NewLas()
+	las.SetLossless(true)
+	las.Load(strings.NewReader(src))
+	b, _ := las.SaveToBuf(false)
+	assert.Equal(t, src, string(b))
+	// without lossless mode file rewritten
+	las.SetLossless(false)
+	b, _ = las.SaveToBuf(false)
+	assert.NotEqual(t, src, string(b))
+}
+
+func TestLosslessEdit(t *testing.T) {
+	src, _ := ioutil.ReadFile("data/2.0/sample_2.0.las")
+	srcLines := strings.Split(string(src), "\n")
+	las := NewLas()
+	las.SetLossless(true)
+	las.Open("data/2.0/sample_2.0.las")
+	// changed parameter rewrite only its line
+	las.WelSec.SetValue("WELL", "NEW WELL")
+	// changed value rewrite only line of record
+	las.Logs[1].V[1] = 100
+	// new parameter written after last line of section
+	las.WelSec.Set(HeaderParam{Name: "RIG", Val: "NEW RIG", Desc: "RIG"})
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	lines := strings.Split(string(b), "\n")
+	assert.Equal(t, len(srcLines)+1, len(lines))
+	diff := make([]string, 0)
+	for i, j := 0, 0; i < len(srcLines); i, j = i+1, j+1 {
+		if srcLines[i] != lines[j] {
+			diff = append(diff, lines[j])
+			if strings.HasPrefix(lines[j], " RIG") {
+				i--
+			}
+		}
+	}
+	assert.Equal(t, 3, len(diff), diff)
+	assert.Contains(t, diff[0], "NEW WELL")
+	assert.Contains(t, diff[1], "NEW RIG")
+	assert.Contains(t, diff[2], "100.000")
+
+	las2 := NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, "NEW WELL", las2.WELL())
+	assert.Equal(t, 100.0, las2.Logs[1].V[1])
+	assert.Equal(t, las.NumPoints(), las2.NumPoints())
+
+	// removed points: lines of records not written
+	for j := range las.Logs {
+		las.Logs[j].SetLen(1)
+	}
+	b, _ = las.SaveToBuf(false)
+	las2 = NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, 1, las2.NumPoints())
+	assert.Equal(t, 1670.0, las2.Dept()[0])
+	assert.Contains(t, string(b), "~OTHER\n     Note: The logging tools")
+}
+
+// missing STEP repaired on load: written as new line, other lines kept
+func TestLosslessMissingStep(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 : version", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 2.0 :", "NULL. -999.25 :", "WELL. W1 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0   10", "1.5   15", "2.0   20"}, "\n")
+	las := NewLas()
+	las.SetLossless(true)
+	_, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, las.STEP())
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "WELL. W1 :\n")
+	assert.Equal(t, len(strings.Split(src, "\n"))+1, len(strings.Split(string(b), "\n")))
+	las2 := NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, "W1", las2.WELL())
+	assert.Equal(t, 0.5, las2.STEP())
+}
+
+func TestLosslessCodepage(t *testing.T) {
+	src, _ := ioutil.ReadFile("test_files/~866.las")
+	las := NewLas(cpd.CP1251)
+	las.SetLossless(true)
+	las.Open("test_files/~866.las")
+	assert.Equal(t, cpd.CP866, las.InputCodepage())
+	// codepage of source kept
+	b, _ := las.SaveToBuf(false)
+	assert.Equal(t, src, b)
+}
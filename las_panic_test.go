@@ -0,0 +1,85 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// loadAll - load source by Load() with standard settings, all checks disabled, all repairs and strict mode, and by LoadStream()
+func loadAll(b []byte) {
+	NewLas().Load(bytes.NewReader(b))
+	checker := NewStdChecker()
+	for name := range checker {
+		checker.Disable(name)
+	}
+	las := NewLas()
+	las.SetChecker(checker)
+	las.Load(bytes.NewReader(b))
+	las = NewLas()
+	las.SetRepairPolicy(NewRepairPolicy(RepairNull, RepairStrt, RepairStep, RepairMissing, RepairNullCurves, RepairReverse, RepairSort, RepairDuplicates, RepairStop))
+	las.Load(bytes.NewReader(b))
+	las = NewLas()
+	las.SetStrict(true)
+	las.Load(bytes.NewReader(b))
+	NewLas().LoadStream(bytes.NewReader(b), func(row *LasRow) error { return nil })
+}
+
+// malformed headers
+var dMalformed = []struct {
+	src  string
+	code WarningCode
+}{
+	{"~", WarnSectionName},
+	{"~\n~\n~A", WarnSectionName},
+	{"~V\nVERS. 2.0 :\n~\n~A\n1 2", WarnSectionName},
+	{"00", WarnOutsideSection},
+	{"VERS. 2.0 :\n~C\nDEPT.M :\n~A\n1", WarnOutsideSection},
+	{"~V\nVERS. 3.0 :\n~\n~A", WarnUndefined},
+	{"~V\nVERS. 3.0 :\n~_\n~_Data\n1 2", WarnUndefined},
+	{"~C\nDEPT.M :\nA. :\n~A\n1\n2 3 4 5\n\"", WarnUndefined},
+	{"~V\nWRAP. YES :\n~C\nDEPT.M :\nA. :\nB. :\n~A\n1\n2 3 4 5 6 7\n8", WarnUndefined},
+	{"~V\nVERS. 3.0 :\nDLM. COMMA :\n~C\nDEPT.M :\nA. :{S}\n~A\n1,\"\n,,,", WarnUndefined},
+	{"~W\nSTEP. 0 :\nNULL. 0 :\n~C\n.\n:\n..\n~A\n~A\n1 2 3", WarnUndefined},
+	{"~V\nWRAP. YES :\n~A\n1\n2 3", WarnUndefined},
+}
+
+// any input loaded without panic, malformed header give warning
+func TestLoadNoPanic(t *testing.T) {
+	for _, tmp := range dMalformed {
+		assert.NotPanics(t, func() { loadAll([]byte(tmp.src)) }, tmp.src)
+		if tmp.code == WarnUndefined {
+			continue
+		}
+		las := NewLas()
+		las.Load(strings.NewReader(tmp.src))
+		found := false
+		for _, w := range las.Warnings {
+			found = found || (w.Code == tmp.code)
+		}
+		assert.True(t, found, tmp.src)
+	}
+	// files of data/ and files truncated after each of first truncLines lines
+	const truncLines = 100
+	filepath.Walk("data", func(path string, info os.FileInfo, err error) error {
+		if (err != nil) || info.IsDir() || (filepath.Ext(path) != ".las") {
+			return nil
+		}
+		b, _ := ioutil.ReadFile(path)
+		assert.NotPanics(t, func() { loadAll(b) }, path)
+		for i, n := 0, 0; (i < len(b)) && (n < truncLines); i++ {
+			if b[i] == '\n' {
+				n++
+				assert.NotPanics(t, func() { loadAll(b[:i]) }, path)
+			}
+		}
+		return nil
+	})
+}
@@ -6,9 +6,11 @@ package glasio
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/softlandia/xlib"
 )
@@ -26,6 +28,8 @@ type HeaderParam struct {
 	Mnemonic string
 	Desc     string // description of parameter
 	lineNo   int    // number of line in source file
+	order    int    // order of parameter added by HeaderSection.Set(), parameters read from file have order 0
+	swapped  bool   // las 1.2: value read after colon, description before colon
 }
 
 // HeaderSection - contain parameters of Well section
@@ -35,12 +39,236 @@ type HeaderSection struct {
 	parse  ParseHeaderParam // function for parse one line
 }
 
-// uniqueName - return unique name
-func (hs HeaderSection) uniqueName(name string) string {
+// dupName - name of n-th instance of duplicated curve or parameter: GR:2, GR:3
+func dupName(iname string, n int) string {
+	return iname + ":" + strconv.Itoa(n)
+}
+
+// add - store parameter read from file
+// first instance of duplicated name keeps name, next instances named IName:2, IName:3 ... by name of first instance
+// names case insensitive: GR, gr - GR, GR:2
+// return new name of parameter, "" if parameter not renamed
+func (hs HeaderSection) add(p HeaderParam) string {
+	if len(p.IName) == 0 {
+		p.IName = p.Name
+	}
+	renamed := ""
+	if _, ok := hs.key(p.Name); ok {
+		same := hs.ByIName(p.IName)
+		base := p.IName
+		if len(same) > 0 {
+			base = same[0].IName
+		}
+		n := len(same) + 1
+		for p.Name = dupName(base, n); ; p.Name = dupName(base, n) {
+			if _, ok := hs.key(p.Name); !ok {
+				break
+			}
+			n++
+		}
+		renamed = p.Name
+	}
+	hs.params[p.Name] = p
+	return renamed
+}
+
+// warningSection - number of section for warnings: lasSecVersion, lasSecWellInfo, lasSecCurInfo, for other sections lasSecIgnore
+func (hs HeaderSection) warningSection() int {
+	switch hs.name {
+	case 'V':
+		return lasSecVersion
+	case 'W':
+		return lasSecWellInfo
+	case 'C':
+		return lasSecCurInfo
+	}
+	return lasSecIgnore
+}
+
+// saveName - return name to write on save, renamed duplicate written with name from source file: GR:2 written as GR
+func (p HeaderParam) saveName() string {
+	if (len(p.IName) > 0) && strings.HasPrefix(p.Name, p.IName+":") {
+		if _, err := strconv.Atoi(p.Name[len(p.IName)+1:]); err == nil {
+			return p.IName
+		}
+	}
+	return p.Name
+}
+
+// ByIName - return all parameters with name iname in source file, in order of lines, name case insensitive
+func (hs HeaderSection) ByIName(iname string) []HeaderParam {
+	res := make([]HeaderParam, 0, 1)
+	for _, p := range hs.sortedParams() {
+		if strings.EqualFold(p.IName, iname) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// sortedParams - return parameters of section in order of lines in source file
+// parameters without line number (added by program) placed after all others in order of addition
+func (hs HeaderSection) sortedParams() []HeaderParam {
+	res := make([]HeaderParam, 0, len(hs.params))
+	for _, p := range hs.params {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return paramLess(res[i], res[j])
+	})
+	return res
+}
+
+// paramLess - order of parameters in section: by number of line, then parameters added by program by order of addition
+func paramLess(a, b HeaderParam) bool {
+	aAdded, bAdded := a.lineNo <= 0, b.lineNo <= 0 // added by program
+	if aAdded != bAdded {
+		return bAdded
+	}
+	if !aAdded && (a.lineNo != b.lineNo) {
+		return a.lineNo < b.lineNo
+	}
+	if a.order != b.order {
+		return a.order < b.order
+	}
+	return a.Name < b.Name
+}
+
+// key - return key of parameter in section, name compared case insensitive, exact match has priority
+func (hs HeaderSection) key(name string) (string, bool) {
 	if _, ok := hs.params[name]; ok {
-		return name + strconv.Itoa(len(hs.params))
+		return name, true
 	}
-	return name
+	for _, p := range hs.sortedParams() {
+		if strings.EqualFold(p.Name, name) {
+			return p.Name, true
+		}
+	}
+	return "", false
+}
+
+// Len - return number of parameters in section
+func (hs HeaderSection) Len() int {
+	return len(hs.params)
+}
+
+// Get - return parameter by name, name case insensitive
+func (hs HeaderSection) Get(name string) (HeaderParam, bool) {
+	k, ok := hs.key(name)
+	return hs.params[k], ok
+}
+
+// Value - return value of parameter, "" if parameter not exist
+func (hs HeaderSection) Value(name string) string {
+	p, _ := hs.Get(name)
+	return p.Val
+}
+
+// Float - return value of parameter as float64
+// error if parameter not exist or value is not number
+func (hs HeaderSection) Float(name string) (float64, error) {
+	p, ok := hs.Get(name)
+	if !ok {
+		return 0, fmt.Errorf("parameter '%s' not exist", name)
+	}
+	v, err := strconv.ParseFloat(p.Val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter '%s' value '%s' is not number", name, p.Val)
+	}
+	return v, nil
+}
+
+// Int - return value of parameter as int, value with zero fraction like "1.0" accepted
+// error if parameter not exist or value is not integer
+func (hs HeaderSection) Int(name string) (int, error) {
+	v, err := hs.Float(name)
+	if err != nil {
+		return 0, err
+	}
+	if (v != math.Trunc(v)) || (math.Abs(v) > math.MaxInt32) {
+		return 0, fmt.Errorf("parameter '%s' value '%s' is not integer", name, hs.Value(name))
+	}
+	return int(v), nil
+}
+
+// Date - return value of parameter as date
+// formats: 25-DEC-1988, 05-Nov-08, 25.12.1988, 1988-12-25, ISO 8601 date-time
+// error if parameter not exist or value is not date
+func (hs HeaderSection) Date(name string) (time.Time, error) {
+	p, ok := hs.Get(name)
+	if !ok {
+		return time.Time{}, fmt.Errorf("parameter '%s' not exist", name)
+	}
+	return parseDate(p.Val)
+}
+
+// dateLayouts - layouts of dates in header
+var dateLayouts = []string{"2-Jan-2006", "2-Jan-06", "2.1.2006", "2.1.06", "2 Jan 2006", "2/1/2006"}
+
+// parseDate - convert date from header to time, name of month case insensitive
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := parseDateTime(s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not date", s)
+}
+
+// Set - add or replace parameter, name of parameter case insensitive
+// replaced parameter keep its place in section and its name, new parameter added after all others
+func (hs HeaderSection) Set(p HeaderParam) {
+	if k, ok := hs.key(p.Name); ok {
+		old := hs.params[k]
+		p.Name, p.IName, p.lineNo, p.order = old.Name, old.IName, old.lineNo, old.order
+	} else {
+		p.lineNo = 0
+		for _, o := range hs.params {
+			if o.order > p.order {
+				p.order = o.order
+			}
+		}
+		p.order++
+	}
+	hs.params[p.Name] = p
+}
+
+// SetValue - set value of parameter, unit and description of existing parameter kept
+// if parameter not exist it added
+func (hs HeaderSection) SetValue(name, value string) {
+	p, ok := hs.Get(name)
+	if !ok {
+		p.Name = name
+	}
+	p.Val = value
+	hs.Set(p)
+}
+
+// Delete - remove parameter from section, return false if parameter not exist
+func (hs HeaderSection) Delete(name string) bool {
+	k, ok := hs.key(name)
+	if ok {
+		delete(hs.params, k)
+	}
+	return ok
+}
+
+// Params - return parameters of section in order: read from file by lines, then added by Set()
+func (hs HeaderSection) Params() []HeaderParam {
+	return hs.sortedParams()
+}
+
+// Names - return names of parameters in order of Params()
+func (hs HeaderSection) Names() []string {
+	res := make([]string, 0, len(hs.params))
+	for _, p := range hs.sortedParams() {
+		res = append(res, p.Name)
+	}
+	return res
 }
 
 // ParseHeaderParam - function to parse one line of header
@@ -70,10 +298,15 @@ func NewOthSection() HeaderSection {
 	sec := HeaderSection{}
 	sec.name = 'O'
 	sec.params = make(map[string]HeaderParam)
-	sec.parse = defParse
+	sec.parse = othParse
 	return sec
 }
 
+// othParse - section ~O contains free text, line stored as is in Val, name of parameter is number of line
+func othParse(s string, i int) (HeaderParam, TWarning) {
+	return HeaderParam{Val: s, Name: strconv.Itoa(i), IName: strconv.Itoa(i), lineNo: i}, TWarning{}
+}
+
 // NewParSection - create section ~P
 func NewParSection() HeaderSection {
 	sec := HeaderSection{}
@@ -108,15 +341,33 @@ func NewWelSection() HeaderSection {
 }
 
 // welParse12 - parse string and create parameter of section ~W
-// this version for las version 1.2
+// this version for las version 1.2: all parameters except STRT, STOP, STEP, NULL with not numeric value
+// store value after colon and description before: "COMP.   COMPANY:  ANY OIL COMPANY LTD."
 func welParse12(s string, i int) (HeaderParam, TWarning) {
 	p := NewHeaderParam(s, i)
-	if p.Name == "WELL" {
-		p.wellName12()
+	if (len(p.Desc) > 0) && p.valueAfterColon() {
+		p.Val, p.Desc = p.Desc, p.Val
+		p.swapped = true
 	}
 	return *p, TWarning{}
 }
 
+// numericWellParams - parameters of section ~W with value before colon in any version of las
+var numericWellParams = map[string]bool{"STRT": true, "STOP": true, "STEP": true, "NULL": true}
+
+// valueAfterColon - return true if in las 1.2 value of parameter of section ~W written after colon
+// parameter read from las 1.2 keep layout of source, other parameters with not numeric value written after colon
+func (p HeaderParam) valueAfterColon() bool {
+	if numericWellParams[strings.ToUpper(p.Name)] {
+		return false
+	}
+	if p.swapped {
+		return true
+	}
+	_, err := strconv.ParseFloat(p.Val, 64)
+	return err != nil
+}
+
 // welParse20 - parse string and create parameter of section ~W
 // this version for las version 2.0
 func welParse20(s string, i int) (HeaderParam, TWarning) {
@@ -127,10 +378,6 @@ func welParse20(s string, i int) (HeaderParam, TWarning) {
 	return *p, TWarning{}
 }
 
-func (p *HeaderParam) wellName12() {
-	p.Val, p.Desc = p.Desc, p.Val
-}
-
 func (p *HeaderParam) wellName20() {
 	// по умолчанию строка параметра разбирается на 4 составляющие: "имя параметра, ед измерения, значение, коментарий"
 	// между точкой и двоеточием ожидается единица измерения и значение параметра
@@ -170,10 +417,24 @@ func NewHeaderParam(s string, i int) *HeaderParam {
 		par.Val = par.Unit
 		par.Unit = ""
 	}
+	if (len(par.Unit) > 0) && spaceAfterDot(s) {
+		if _, err := strconv.ParseFloat(par.Val, 64); err != nil {
+			// "COMP.   ANY OIL COMPANY : company" - space after dot, unit not exist, value contains spaces
+			par.Val = par.Unit + " " + par.Val
+			par.Unit = ""
+		}
+	}
 	par.Desc = paramFields[3]
 	return par
 }
 
+// spaceAfterDot - return true if the first dot of string followed by space
+// unit of parameter must be written immediately after dot
+func spaceAfterDot(s string) bool {
+	i := strings.IndexRune(s, '.')
+	return (i >= 0) && (i+1 < len(s)) && ((s[i+1] == ' ') || (s[i+1] == '\t'))
+}
+
 // ParseParamStr - parse string from las file
 // return slice with 4 string and error if occure
 // before process input string 2 or more space replace on 1 space
@@ -203,33 +464,32 @@ func ParseParamStr(s string) (f [4]string) {
 		return
 	}
 	s = strings.TrimSpace(s[iDot+1:])
-	f[1], iDot = xlib.StrCopyStop(s, ' ', ':')
+	// description already cut, colon may be only part of value (time: 10:00:00)
+	f[1], iDot = xlib.StrCopyStop(s, ' ')
 	f[1] = strings.TrimSpace(f[1])
 	if iDot >= len(s) {
 		return
 	}
-	s = strings.TrimSpace(s[iDot+1:])
-	f[2], _ = xlib.StrCopyStop(s, ':')
-	f[2] = strings.TrimSpace(f[2])
+	f[2] = strings.TrimSpace(s[iDot+1:])
 	return
 }
 
-// NewCurveHeaderParam - create new object LasParam
-// STRT.     m       10.0     : start
-// field[0] field[1] field[2] field[3]
+// NewCurveHeaderParam - create new object LasParam from line of section ~C
+// API code of curve stored in Val
 func NewCurveHeaderParam(s string, i int) *HeaderParam {
 	par := new(HeaderParam)
 	par.lineNo = i
-	paramFields := ParseCurveStr(s)
-	par.Name = paramFields[0]
-	par.Unit = paramFields[1]
-	par.Desc = paramFields[2]
+	c := ParseCurveDef(s)
+	par.Name = c.Name
+	par.Unit = c.Unit
+	par.Val = c.API
+	par.Desc = c.Desc
 	return par
 }
 
 const defCurveName = "-EL-" // curve name for null input
 
-// ParseCurveStr - parse input string to 3 separated string
+// ParseCurveStr - parse input string to 3 separated string, API code not returned, use ParseCurveDef()
 // " пс повт . мВ      : 7 кр сам"
 //   ^^^^^^^   ^^        ^^^^^^^^
 //   name      unit      description
@@ -237,39 +497,22 @@ const defCurveName = "-EL-" // curve name for null input
 // f[1] - unit
 // f[0] - name
 func ParseCurveStr(s string) (f [3]string) {
-	f[0] = ""
-	f[1] = ""
-	f[2] = ""
-	s = PrepareParamStr(s)
-
-	iComma := strings.LastIndex(s, ":") //comment parse first, cut string after
-	commentFlag := (iComma >= 0)
-	if commentFlag {
-		f[2] = s[iComma+1:]
-		s = strings.TrimSpace(s[:iComma])
-	}
-	//if comma not found, string not change
-	//now s contains only name and unit
-	iDot := strings.Index(s, ".")
-	if iDot < 0 { //if dot not found, all string is Curve name
-		f[0] = strings.TrimSpace(s)
-		return
-	}
-	f[0] = strings.TrimSpace(s[:iDot])
-	if len(f[0]) == 0 {
-		f[0] = defCurveName // case empty curve name
-	}
-
-	f[1] = strings.TrimSpace(s[iDot+1:])
+	c := ParseCurveDef(s)
+	f[0] = c.Name
+	f[1] = c.Unit
+	f[2] = c.Desc
 	return
 }
 
 //LasCurve - class to store one log in Las
 type LasCurve struct {
 	HeaderParam
-	Index int
-	D     []float64
-	V     []float64
+	API    string // API code of curve: "7 350 02 00"
+	Index  int
+	Format string // format specifier of las 3.0: F10.4, E, S, A, DD/MM/YYYY, empty for las 1.2 and 2.0
+	D      []float64
+	V      []float64
+	S      []string // values of string curve (IsString() == true), V for this curve contains NULL
 }
 
 // NewLasCurve - create new object LasCurve
@@ -277,11 +520,16 @@ type LasCurve struct {
 // las - pointer to container
 func NewLasCurve(s string, las *Las) LasCurve {
 	lc := LasCurve{}
-	curveFields := ParseCurveStr(s)
-	lc.IName = curveFields[0]
+	c := ParseCurveDef(s)
+	for _, w := range c.Warnings {
+		las.addWarning(newWarning(WarnCurveDef, lasSecCurInfo, las.currentLine, "curve '%s': %s", c.Name, w))
+	}
+	lc.IName = c.Name
 	lc.Name = las.Logs.UniqueName(lc.IName)
-	lc.Unit = curveFields[1]
-	lc.Desc = curveFields[2]
+	lc.Unit = c.Unit
+	lc.API = c.API
+	lc.Desc = c.Desc
+	lc.lineNo = las.currentLine             // on load currentLine is number of line of curve
 	lc.Index = len(las.Logs)                // index of new curve == number of curves already in container
 	lc.Mnemonic = las.GetMnemonic(lc.IName) // мнемонику определяем по входному имени кривой
 	// вместимость слайсов для хранения данных равна количеству строк в исходном файле
@@ -290,6 +538,38 @@ func NewLasCurve(s string, las *Las) LasCurve {
 	return lc
 }
 
+// parseFormat - extract format specifier "{F10.4}" and association "| name" from description of las 3.0 curve
+func (o *LasCurve) parseFormat() {
+	d := o.Desc
+	if i := strings.LastIndex(d, "|"); i >= 0 {
+		d = d[:i]
+	}
+	if i := strings.LastIndex(d, "{"); i >= 0 {
+		if j := strings.Index(d[i:], "}"); j > 0 {
+			o.Format = strings.TrimSpace(d[i+1 : i+j])
+			d = d[:i] + d[i+j+1:]
+		}
+	}
+	o.Desc = strings.TrimSpace(d)
+}
+
+// IsString - return true if values of curve are strings
+// numeric formats: empty, F, E, I with optional width: F10.4, I5, also arrays AF10.4
+// all other formats (S, A, date and time) are string
+func (o *LasCurve) IsString() bool {
+	f := strings.ToUpper(o.Format)
+	if (len(f) > 1) && (f[0] == 'A') && strings.ContainsRune("FEI", rune(f[1])) {
+		f = f[1:] // array of numbers
+	}
+	if len(f) == 0 {
+		return false
+	}
+	if !strings.ContainsRune("FEI", rune(f[0])) {
+		return true
+	}
+	return strings.Trim(f[1:], "0123456789.") != ""
+}
+
 // String - return LasCurve as string
 func (o LasCurve) String() string {
 	return fmt.Sprintf("[\n{\n\"IName\": \"%s\",\n\"Name\": \"%s\",\n\"Mnemonic\": \"%s\",\n\"Unit\": \"%s\",\"Val\": \"%s\",\n\"Desc\": \"%s\"\n}\n]", o.IName, o.Name, o.Mnemonic, o.Unit, o.Val, o.Desc)
@@ -343,11 +623,45 @@ func (curves LasCurves) IsPresent(curveName string) bool {
 }
 
 // UniqueName - make new unique name of curve if it duplicated
+// first curve keeps name, next curves with the same input name named GR:2, GR:3 ... by name of first curve
+// names case insensitive: GR, gr - GR, GR:2
 func (curves LasCurves) UniqueName(curveName string) string {
-	if curves.IsPresent(curveName) {
-		return curveName + strconv.Itoa(len(curves))
+	if !curves.isPresentFold(curveName) {
+		return curveName
+	}
+	same := curves.ByIName(curveName)
+	base := curveName
+	if len(same) > 0 {
+		base = same[0].IName
+	}
+	n := len(same) + 1
+	name := dupName(base, n)
+	for curves.isPresentFold(name) {
+		n++
+		name = dupName(base, n)
+	}
+	return name
+}
+
+// isPresentFold - return true if curve with name curveName present in container, name case insensitive
+func (curves LasCurves) isPresentFold(curveName string) bool {
+	for _, c := range curves {
+		if strings.EqualFold(c.Name, curveName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ByIName - return all curves with name iname in source file, name case insensitive
+func (curves LasCurves) ByIName(iname string) LasCurves {
+	res := make(LasCurves, 0, 1)
+	for _, c := range curves {
+		if strings.EqualFold(c.IName, iname) {
+			res = append(res, c)
+		}
 	}
-	return curveName
+	return res
 }
 
 // Cmp - compare current curves container with another
@@ -4,9 +4,12 @@
 package glasio
 
 import (
+	"bytes"
 	"fmt"
 	fp "path/filepath"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -39,7 +42,7 @@ func TestReadWellParam(t *testing.T) {
 	for i, tmp := range dReadWellParamStep {
 		//las.ReadWellParam(tmp.s)
 		hp, _ := las.WelSec.parse(tmp.s, 0)
-		las.WelSec.params["STEP"] = hp
+		las.WelSec.Set(hp)
 		assert.Equal(t, tmp.v, las.STEP(), fmt.Sprintf("<ReadWellParam> on test %d return STEP: '%f' expect: '%f'\n", i, las.STEP(), tmp.v))
 	}
 }
@@ -75,6 +78,7 @@ var dParseParamStr = []tParseParamStr{
 	{"VERS.             1.20: cp_866  ", "VERS.1.20:cp_866", "VERS", "1.20", "", "cp_866"},                //18
 	{"NULL.   -999.250   :NULL VALUE", "NULL.-999.250:NULL VALUE", "NULL", "-999.250", "", "NULL VALUE"},  //19
 	{"VERS.      2.0 :[Softland]", "VERS.2.0:[Softland]", "VERS", "2.0", "", "[Softland]"},                //20
+	{"STRT.S 10:00:00 : start", "STRT.S 10:00:00:start", "STRT", "S", "10:00:00", "start"},                //21
 	//{"WELL. Примерная 101/\"бис\" :well", "WELL.Примерная 101/\"бис\":well", "WELL", "Примерная 101/\"бис\"", "", "well"}, //21
 }
 
@@ -173,7 +177,7 @@ var dParseCurveStr = []tParseCurveStr{
 	{" .      : ", "-EL-", "", ""},                                //12
 	{" .mv      : ", "-EL-", "mv", ""},                            //13
 	{" .mv      :sp", "-EL-", "mv", "sp"},                         //14
-	{" .m v     :sp", "-EL-", "m v", "sp"},                        //15
+	{" .m v     :sp", "-EL-", "m", "sp"},                          //15 "v" is API code
 }
 
 func TestNewCurveHeaderParam(t *testing.T) {
@@ -242,8 +246,112 @@ func TestLasCurveString(t *testing.T) {
 	assert.Equal(t, "[\n{\n\"IName\": \"A\",\n\"Name\": \"A\",\n\"Mnemonic\": \"\",\n\"Unit\": \"US/M\",\"Val\": \"\",\n\"Desc\": \"2 SONIC TRANSIT TIME\"\n}\n]", las.Logs[1].String())
 	//-EL-1.      :
 	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-\",\n\"Mnemonic\": \"\",\n\"Unit\": \"\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[4].String())
-	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-5\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[5].String())
-	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-6\",\n\"Mnemonic\": \"\",\n\"Unit\": \"v/v\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[6].String())
-	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-7\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m V\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[7].String())
-	assert.Equal(t, "[\n{\n\"IName\": \"-EL-5\",\n\"Name\": \"-EL-58\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[8].String())
+	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-:2\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[5].String())
+	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-:3\",\n\"Mnemonic\": \"\",\n\"Unit\": \"v/v\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[6].String())
+	assert.Equal(t, "[\n{\n\"IName\": \"-EL-\",\n\"Name\": \"-EL-:4\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[7].String())
+	assert.Equal(t, "[\n{\n\"IName\": \"-EL-5\",\n\"Name\": \"-EL-5\",\n\"Mnemonic\": \"\",\n\"Unit\": \"m\",\"Val\": \"\",\n\"Desc\": \"\"\n}\n]", las.Logs[8].String())
+}
+
+func TestHeaderSection(t *testing.T) {
+	las := NewLas()
+	_, err := las.Open("data/2.0/sample_2.0.las")
+	assert.Nil(t, err)
+	sec := las.WelSec
+	// lookup case insensitive
+	p, ok := sec.Get("comp")
+	assert.True(t, ok)
+	assert.Equal(t, "ANY OIL COMPANY INC.", p.Val)
+	assert.Equal(t, "COMPANY", p.Desc)
+	_, ok = sec.Get("XXX")
+	assert.False(t, ok)
+	assert.Equal(t, "", sec.Value("XXX"))
+	// typed getters
+	v, err := sec.Float("strt")
+	assert.Nil(t, err)
+	assert.Equal(t, 1670.0, v)
+	_, err = sec.Float("COMP")
+	assert.NotNil(t, err)
+	_, err = sec.Float("XXX")
+	assert.NotNil(t, err)
+	d, err := sec.Date("DATE")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(1986, 12, 13, 0, 0, 0, 0, time.UTC), d)
+	n, err := las.ParSec.Int("DFD")
+	assert.Nil(t, err)
+	assert.Equal(t, 1525, n)
+	_, err = las.ParSec.Int("MATR")
+	assert.NotNil(t, err)
+
+	// setters keep order
+	names := sec.Names()
+	assert.Equal(t, sec.Len(), len(names))
+	assert.Equal(t, "STRT", names[0])
+	sec.SetValue("well", "NEW WELL")
+	sec.Set(HeaderParam{Name: "RIG", Val: "R1", Desc: "RIG"})
+	sec.SetValue("ABC", "1")
+	sec.Set(HeaderParam{Name: "rig", Val: "R2", Desc: "RIG NAME"})
+	assert.Equal(t, "NEW WELL", las.WELL())
+	assert.Equal(t, append(names, "RIG", "ABC"), sec.Names())
+	params := sec.Params()
+	assert.Equal(t, "R2", params[len(params)-2].Val)
+	assert.Equal(t, "RIG NAME", params[len(params)-2].Desc)
+	sec.Set(HeaderParam{Name: "strt", Val: "5"})
+	assert.Equal(t, "STRT", sec.Names()[0])
+	assert.Equal(t, 5.0, las.STRT())
+	// delete
+	assert.True(t, sec.Delete("Rig"))
+	assert.False(t, sec.Delete("RIG"))
+	assert.Equal(t, append(names, "ABC"), sec.Names())
+	// changes saved in order of section
+	b, _ := las.SaveToBuf(false)
+	las2 := NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, "NEW WELL", las2.WELL())
+	assert.Equal(t, "ABC", las2.WelSec.Names()[las2.WelSec.Len()-1])
+}
+
+// order of parameters is strict: for any two parameters only one less than other
+func TestParamLess(t *testing.T) {
+	params := []HeaderParam{
+		{Name: "A", lineNo: 0, order: 2}, {Name: "B", lineNo: -1, order: 1}, {Name: "C", lineNo: 5},
+		{Name: "D", lineNo: 3}, {Name: "E", lineNo: 0, order: 1}, {Name: "F", lineNo: 3},
+	}
+	for _, a := range params {
+		assert.False(t, paramLess(a, a), a.Name)
+		for _, b := range params {
+			if a.Name != b.Name {
+				assert.NotEqual(t, paramLess(a, b), paramLess(b, a), a.Name+" "+b.Name)
+			}
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return paramLess(params[i], params[j]) })
+	names := ""
+	for _, p := range params {
+		names += p.Name
+	}
+	assert.Equal(t, "DFCBEA", names)
+}
+
+type tParseDate struct {
+	s string
+	d time.Time
+}
+
+var dParseDate = []tParseDate{
+	{"25-DEC-1988", time.Date(1988, 12, 25, 0, 0, 0, 0, time.UTC)},
+	{"05-Nov-08", time.Date(2008, 11, 5, 0, 0, 0, 0, time.UTC)},
+	{"5-nov-2008", time.Date(2008, 11, 5, 0, 0, 0, 0, time.UTC)},
+	{"13.03.2019", time.Date(2019, 3, 13, 0, 0, 0, 0, time.UTC)},
+	{"2019-03-13", time.Date(2019, 3, 13, 0, 0, 0, 0, time.UTC)},
+	{"2019-03-13T10:20:00", time.Date(2019, 3, 13, 10, 20, 0, 0, time.UTC)},
+}
+
+func TestParseDate(t *testing.T) {
+	for _, tmp := range dParseDate {
+		d, err := parseDate(tmp.s)
+		assert.Nil(t, err, tmp.s)
+		assert.Equal(t, tmp.d, d, tmp.s)
+	}
+	_, err := parseDate("13 DECEMBER")
+	assert.NotNil(t, err)
 }
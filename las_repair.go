@@ -0,0 +1,322 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// repairs of las on load
+
+package glasio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Repair - name of repair applied on load
+type Repair string
+
+// repairs of header, performed after load of header
+const (
+	RepairNull    Repair = "NULL"    // NULL = 0 replaced by standard null value
+	RepairStrt    Repair = "STRT"    // STRT not exist: taken from first depth of data
+	RepairStep    Repair = "STEP"    // STEP not exist or 0: taken from step of data, for irregular index set to 0
+	RepairMissing Repair = "MISSING" // missing values of line of data section set to NULL, if disabled line ignored
+)
+
+// repairs of data, performed after load of data section in order: NULL_CURVES, REVERSE, SORT, DUPLICATES, STOP
+const (
+	RepairNullCurves Repair = "NULL_CURVES" // curves contain only NULL removed
+	RepairReverse    Repair = "REVERSE"     // upward log (first depth greater than last) reversed, STRT and STOP swapped, sign of STEP changed
+	RepairSort       Repair = "SORT"        // rows sorted by ascending depth
+	RepairDuplicates Repair = "DUPLICATES"  // rows with depth already present removed, first row kept
+	RepairStop       Repair = "STOP"        // STOP recomputed from last depth of data
+)
+
+// RepairPolicy - repairs applied on load, specify by Las.SetRepairPolicy()
+type RepairPolicy map[Repair]bool
+
+// NewRepairPolicy - create policy with specified repairs, NewRepairPolicy() - no repairs
+func NewRepairPolicy(repairs ...Repair) RepairPolicy {
+	p := make(RepairPolicy)
+	p.Enable(repairs...)
+	return p
+}
+
+// NewStdRepairPolicy - create policy used by default: NULL, STRT, STEP, MISSING
+func NewStdRepairPolicy() RepairPolicy {
+	return NewRepairPolicy(RepairNull, RepairStrt, RepairStep, RepairMissing)
+}
+
+// Enable - enable repairs
+func (p RepairPolicy) Enable(repairs ...Repair) {
+	for _, r := range repairs {
+		p[r] = true
+	}
+}
+
+// Disable - disable repairs
+func (p RepairPolicy) Disable(repairs ...Repair) {
+	for _, r := range repairs {
+		delete(p, r)
+	}
+}
+
+// RepairRecord - applied repair, what and where changed
+type RepairRecord struct {
+	Repair Repair `json:"repair"`
+	Line   int    `json:"line"` // number of line from 1, 0 if repair not related to one line
+	Item   string `json:"item"` // repaired parameter or curve: STRT, NULL, GR
+	Old    string `json:"old"`  // value before repair, "" if value not exist
+	New    string `json:"new"`  // value after repair, "" if value removed
+	Desc   string `json:"desc"`
+}
+
+// String - return record as: "STRT: line 6, STRT: '' -> '1670', taken from data"
+func (r RepairRecord) String() string {
+	var sb strings.Builder
+	sb.WriteString(string(r.Repair) + ":")
+	if r.Line > 0 {
+		fmt.Fprintf(&sb, " line %d,", r.Line)
+	}
+	if len(r.Item) > 0 {
+		fmt.Fprintf(&sb, " %s:", r.Item)
+	}
+	if (len(r.Old) > 0) || (len(r.New) > 0) {
+		fmt.Fprintf(&sb, " '%s' -> '%s',", r.Old, r.New)
+	}
+	sb.WriteString(" " + r.Desc)
+	return sb.String()
+}
+
+// RepairLog - all repairs applied on load, stored in Las.Repairs
+type RepairLog []RepairRecord
+
+// String - return all records, one record per line
+func (rl RepairLog) String() string {
+	s := make([]string, len(rl))
+	for i, r := range rl {
+		s[i] = r.String()
+	}
+	return strings.Join(s, "\n")
+}
+
+// SetRepairPolicy - set repairs applied on load, applied repairs added to Las.Repairs
+// nil - NewStdRepairPolicy()
+func (las *Las) SetRepairPolicy(p RepairPolicy) {
+	las.repairPolicy = p
+}
+
+// getRepairPolicy - return policy specified by SetRepairPolicy() or standard policy
+func (las *Las) getRepairPolicy() RepairPolicy {
+	if las.repairPolicy == nil {
+		return NewStdRepairPolicy()
+	}
+	return las.repairPolicy
+}
+
+// repairEnabled - return true if repair enabled by policy
+func (las *Las) repairEnabled(r Repair) bool {
+	return las.getRepairPolicy()[r]
+}
+
+func (las *Las) addRepair(r RepairRecord) {
+	las.Repairs = append(las.Repairs, r)
+}
+
+// addMissingRepair - add record of missing values of data section set to NULL, values - number of values
+// on streaming read records not stored, only number of values and first line kept, see streamRepairs()
+func (las *Las) addMissingRepair(r RepairRecord, values int) {
+	if !las.streaming {
+		las.addRepair(r)
+		return
+	}
+	if las.missingVals == 0 {
+		las.missingLine = r.Line
+	}
+	las.missingVals += values
+}
+
+// streamRepairs - add one record of all missing values set to NULL on streaming read
+func (las *Las) streamRepairs() {
+	if las.missingVals > 0 {
+		las.addRepair(RepairRecord{RepairMissing, las.missingLine, "", "", "", fmt.Sprintf("%d missing values of data section set to NULL, first line: %d", las.missingVals, las.missingLine)})
+	}
+}
+
+// repairParam - add record of repair of parameter of section ~W, old - parameter before repair, empty if not exist
+// if value not changed record not added
+func (las *Las) repairParam(r Repair, name string, old HeaderParam, desc string) {
+	p, _ := las.WelSec.Get(name)
+	if p.Val != old.Val {
+		las.addRepair(RepairRecord{r, old.lineNo, name, old.Val, p.Val, desc})
+	}
+}
+
+// pointSourceLine - return number of first line of point i in source, 0 if unknown
+func (las *Las) pointSourceLine(i int) int {
+	if (i >= 0) && (i < len(las.pointLines)) {
+		return las.pointLines[i].first
+	}
+	return 0
+}
+
+// repairData - apply repairs of data enabled by policy, called after load of data section
+func (las *Las) repairData() {
+	if len(las.Logs) == 0 {
+		return
+	}
+	p := las.getRepairPolicy()
+	if p[RepairNullCurves] {
+		las.stripNullCurves()
+	}
+	if p[RepairReverse] {
+		las.reverseUpward()
+	}
+	if p[RepairSort] {
+		las.sortAscending()
+	}
+	if p[RepairDuplicates] {
+		las.dropDuplicates()
+	}
+	if p[RepairStop] {
+		las.recomputeStop()
+	}
+}
+
+// reorder - rebuild data of all curves and source lines of points, new point i is old point order[i]
+// source lines of records in order of file kept in recordLines for lossless save
+func (las *Las) reorder(order []int) {
+	if len(las.pointLines) == las.NumPoints() {
+		if las.recordLines == nil {
+			las.recordLines = las.pointLines
+		}
+		lines := make([]lineRange, len(order))
+		for i, k := range order {
+			lines[i] = las.pointLines[k]
+		}
+		las.pointLines = lines
+	}
+	for j := range las.Logs {
+		c := &las.Logs[j]
+		d := make([]float64, len(order))
+		v := make([]float64, len(order))
+		for i, k := range order {
+			d[i], v[i] = c.D[k], c.V[k]
+		}
+		c.D, c.V = d, v
+		if c.IsString() && (len(c.S) > 0) {
+			s := make([]string, len(order))
+			for i, k := range order {
+				s[i] = c.S[k]
+			}
+			c.S = s
+		}
+	}
+}
+
+// stripNullCurves - remove curves contains only NULL from Logs and section ~C, index curve not removed
+func (las *Las) stripNullCurves() {
+	if las.NumPoints() == 0 {
+		return
+	}
+	null := las.NULL()
+	curves := make(LasCurves, 0, len(las.Logs))
+	for j, c := range las.Logs {
+		if (j > 0) && !c.IsString() && allEqual(c.V, null) {
+			las.addRepair(RepairRecord{RepairNullCurves, c.lineNo, c.Name, "", "", "curve contains only NULL, removed"})
+			las.CurSec.Delete(c.Name)
+			continue
+		}
+		c.Index = len(curves)
+		curves = append(curves, c)
+	}
+	las.Logs = curves
+}
+
+// allEqual - return true if all values equal to v
+func allEqual(values []float64, v float64) bool {
+	for _, x := range values {
+		if x != v {
+			return false
+		}
+	}
+	return true
+}
+
+// reverseUpward - reverse order of points if first depth greater than last
+func (las *Las) reverseUpward() {
+	d := las.Dept()
+	n := len(d)
+	if (n < 2) || (d[0] <= d[n-1]) {
+		return
+	}
+	las.addRepair(RepairRecord{RepairReverse, 0, las.Logs[0].Name, fmt.Sprintf("%g..%g", d[0], d[n-1]), fmt.Sprintf("%g..%g", d[n-1], d[0]), "upward log reversed"})
+	order := make([]int, n)
+	for i := range order {
+		order[i] = n - 1 - i
+	}
+	las.reorder(order)
+	strt, okStrt := las.WelSec.Get("STRT")
+	stop, okStop := las.WelSec.Get("STOP")
+	if okStrt && okStop {
+		las.setIndexParam(HeaderParam{Val: stop.Val, Name: "STRT"})
+		las.setIndexParam(HeaderParam{Val: strt.Val, Name: "STOP"})
+		las.repairParam(RepairReverse, "STRT", strt, "STRT and STOP swapped")
+		las.repairParam(RepairReverse, "STOP", stop, "STRT and STOP swapped")
+	}
+	if step := las.STEP(); (step != 0) && (step != las.NULL()) && !las.IsStepEmpty() {
+		old, _ := las.WelSec.Get("STEP")
+		las.setStep(-step)
+		las.repairParam(RepairReverse, "STEP", old, "sign of STEP changed")
+	}
+}
+
+// sortAscending - sort points by ascending depth, order of points with equal depth kept
+func (las *Las) sortAscending() {
+	d := las.Dept()
+	if sort.Float64sAreSorted(d) {
+		return
+	}
+	order := make([]int, len(d))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return d[order[i]] < d[order[j]]
+	})
+	moved := 0
+	for i, k := range order {
+		if i != k {
+			moved++
+		}
+	}
+	las.addRepair(RepairRecord{RepairSort, 0, las.Logs[0].Name, "", "", fmt.Sprintf("rows sorted by ascending depth, %d rows moved", moved)})
+	las.reorder(order)
+}
+
+// dropDuplicates - remove points with depth already present, first point kept
+func (las *Las) dropDuplicates() {
+	d := las.Dept()
+	seen := make(map[float64]bool, len(d))
+	order := make([]int, 0, len(d))
+	for i, v := range d {
+		if seen[v] {
+			las.addRepair(RepairRecord{RepairDuplicates, las.pointSourceLine(i), las.Logs[0].Name, las.formatIndex(v, "%g"), "", "row with duplicated depth removed"})
+			continue
+		}
+		seen[v] = true
+		order = append(order, i)
+	}
+	if len(order) < len(d) {
+		las.reorder(order)
+	}
+}
+
+// recomputeStop - set STOP to last depth of data
+func (las *Las) recomputeStop() {
+	d := las.Dept()
+	if (len(d) == 0) || (!las.IsStopEmpty() && (las.STOP() == d[len(d)-1])) {
+		return
+	}
+	old, _ := las.WelSec.Get("STOP")
+	las.setStop(d[len(d)-1])
+	las.repairParam(RepairStop, "STOP", old, "STOP recomputed from data")
+}
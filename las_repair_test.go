@@ -0,0 +1,117 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairPolicy(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. 0 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "~A", "1.0 10 0", "2.0 20", "3.0 30 0"}, "\n")
+	// standard policy: NULL, STRT, STEP, MISSING
+	las := NewLas()
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, RepairLog{
+		{RepairNull, 7, "NULL", "0", "-999.25", "NULL = 0 replaced by standard null value"},
+		{RepairStrt, 0, "STRT", "", "1", "STRT taken from data"},
+		{RepairMissing, 14, "B", "", "-999.25000", "data not present, value set to NULL"},
+	}, las.Repairs)
+	assert.Equal(t, "NULL: line 7, NULL: '0' -> '-999.25', NULL = 0 replaced by standard null value", las.Repairs[0].String())
+	assert.Equal(t, -999.25, las.Logs[2].V[1])
+
+	// no repairs: NULL and STRT not changed, line with missing value ignored
+	las = NewLas()
+	las.SetRepairPolicy(NewRepairPolicy())
+	n, err = las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 0, len(las.Repairs))
+	assert.Equal(t, 0.0, las.NULL())
+	assert.True(t, las.IsStrtEmpty())
+	assert.Equal(t, []float64{1, 3}, las.Dept())
+
+	// STOP recomputed from data, check of data not failed
+	las = NewLas()
+	las.SetRepairPolicy(NewRepairPolicy(RepairStop))
+	las.Load(strings.NewReader(strings.Replace(src, "STOP.M 3.0", "STOP.M 5.0", 1)))
+	assert.Equal(t, RepairLog{{RepairStop, 5, "STOP", "5.0", "3", "STOP recomputed from data"}}, las.Repairs)
+	assert.Equal(t, 3.0, las.STOP())
+	for _, w := range las.Warnings {
+		assert.NotEqual(t, WarnDataStop, w.Code)
+	}
+}
+
+func TestRepairData(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 3.0 :", "STOP.M 1.5 :", "STEP.M -1.0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "~A",
+		"3.0 30 -999.25", "2.0 20 -999.25", "2.0 21 -999.25", "1.0 10 -999.25", "1.5 15 -999.25"}, "\n")
+	p := NewStdRepairPolicy()
+	p.Enable(RepairNullCurves, RepairReverse, RepairSort, RepairDuplicates, RepairStop)
+	las := NewLas()
+	las.SetRepairPolicy(p)
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, 2, len(las.Logs))
+	assert.Equal(t, 1, las.Logs[1].Index)
+	assert.Equal(t, []float64{1, 1.5, 2, 3}, las.Dept())
+	assert.Equal(t, []float64{10, 15, 21, 30}, las.Logs[1].V)
+	assert.Equal(t, 1.5, las.STRT())
+	assert.Equal(t, 3.0, las.STOP())
+	assert.Equal(t, 1.0, las.STEP())
+	assert.Equal(t, RepairLog{
+		{RepairNullCurves, 12, "B", "", "", "curve contains only NULL, removed"},
+		{RepairReverse, 0, "DEPT", "3..1.5", "1.5..3", "upward log reversed"},
+		{RepairReverse, 5, "STRT", "3.0", "1.5", "STRT and STOP swapped"},
+		{RepairReverse, 6, "STOP", "1.5", "3.0", "STRT and STOP swapped"},
+		{RepairReverse, 7, "STEP", "-1.0", "1", "sign of STEP changed"},
+		{RepairSort, 0, "DEPT", "", "", "rows sorted by ascending depth, 2 rows moved"},
+		{RepairDuplicates, 15, "DEPT", "2", "", "row with duplicated depth removed"},
+	}, las.Repairs)
+	// source lines follow reordered points, removed curve not in section ~C
+	for i, line := range []int{17, 18, 16, 14} {
+		assert.Equal(t, line, las.pointSourceLine(i))
+	}
+	_, ok := las.CurSec.Get("B")
+	assert.False(t, ok)
+	las.SetLossless(true)
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "\nB. :")
+	assert.Contains(t, string(b), "~A\n1.0000     10.0000    \n1.5000     15.0000    \n2.0000     21.0000    \n3.0000     30.0000    ")
+	// repairs disabled: data as in file
+	las = NewLas()
+	las.Load(strings.NewReader(src))
+	assert.Equal(t, 0, len(las.Repairs))
+	assert.Equal(t, []float64{3, 2, 2, 1, 1.5}, las.Dept())
+}
+
+// second load on the same las not depends on first
+func TestLoadTwice(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. -999.25 :", "WELL. W1 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "~A", "3.0 30 3", "1.0 10", "2.0 20 2", "2.0 20 2"}, "\n")
+	las := NewLas()
+	las.SetRepairPolicy(NewStdRepairPolicy())
+	las.getRepairPolicy().Enable(RepairSort, RepairDuplicates)
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	repairs, warnings := las.Repairs, las.Warnings
+	for i := 0; i < 2; i++ {
+		m, err := las.Load(strings.NewReader(src))
+		assert.Nil(t, err)
+		assert.Equal(t, n, m)
+		assert.Equal(t, 3, len(las.Logs))
+		assert.Equal(t, []float64{1, 2, 3}, las.Dept())
+		assert.Equal(t, repairs, las.Repairs)
+		assert.Equal(t, warnings, las.Warnings)
+	}
+}
@@ -0,0 +1,179 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// streaming read of data section
+
+package glasio
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// LasRow - one row of data section
+// on streaming read the same object is passed to handler for each row, copy values if they needed after handler return
+type LasRow struct {
+	Line int       // number of line in source file
+	V    []float64 // values of all curves in order of section ~C, V[0] - depth
+	S    []string  // values of string curves (las 3.0), for numeric curves ""
+}
+
+// Dept - return depth of row
+func (r *LasRow) Dept() float64 {
+	return r.V[0]
+}
+
+// reset - prepare row to store n values
+func (r *LasRow) reset(n int) {
+	if cap(r.V) < n {
+		r.V = make([]float64, n)
+		r.S = make([]string, n)
+	}
+	r.V = r.V[:n]
+	r.S = r.S[:n]
+	for i := range r.S {
+		r.S[i] = ""
+	}
+}
+
+// RowHandler - function receive rows of data section on streaming read
+// if handler return error, reading stopped and error returned from LoadStream
+type RowHandler func(row *LasRow) error
+
+// LoadStream - load las from reader without storing data
+// header loaded as by Load(), rows of data section passed to handler one by one, curves in Logs stay empty
+// warnings on data section the same as by Load(), checks of data performed row by row
+// values of data section not kept: missing values set to NULL added to Repairs as one record with number of values and first line
+// STRT and STEP repaired and type of index (regular, gaps, irregular) determined only by first lines of data section,
+// by default 100 lines, specify by SetStreamHead(), if spacing of depth changes after them STEP and warnings may differ from Load()
+// returns number of rows passed to handler
+func (las *Las) LoadStream(reader io.Reader, handler RowHandler) (int, error) {
+	var err error
+	if reader == nil {
+		return 0, &LoadError{File: las.FileName, Err: ErrNilReader}
+	}
+	if handler == nil {
+		return 0, &LoadError{File: las.FileName, Err: ErrNilHandler}
+	}
+	las.resetLoad()
+	las.streaming = true
+	las.Reader, err = las.newReader(reader)
+	if err != nil {
+		return 0, &LoadError{File: las.FileName, Err: ErrDecode, Cause: err} //FATAL error - file cannot be decoded to UTF-8
+	}
+	las.scanner = las.newScanner()
+	las.readHeaderRows()
+	m, _ := las.LoadHeader()
+	las.readDataHead()
+	if err = las.scanError(); err != nil {
+		return 0, err
+	}
+	if err = las.strictHeader(); err != nil {
+		return 0, err
+	}
+	if err = las.checkHeader(); err != nil {
+		return 0, err
+	}
+	i := m
+	next := func() (string, bool) {
+		if err != nil { // handler return error, reading stopped
+			return "", false
+		}
+		if i < len(las.rows) { // lines read by readDataHead()
+			i++
+			return las.rows[i-1], true
+		}
+		if !las.scanner.Scan() {
+			return "", false
+		}
+		return las.scanner.Text(), true
+	}
+	n := 0
+	store := func(fields []string) {
+		if (err != nil) || !las.parseDataRow(fields, &las.row) {
+			return
+		}
+		n++
+		las.stat.add(las, las.row.V, las.recFirst)
+		err = handler(&las.row)
+	}
+	las.stat = &dataStat{headSize: las.getStreamHead()}
+	las.currentLine = m - 1
+	sectionLine := las.loadData(next, store)
+	las.streamRepairs()
+	if las.strictErr != nil {
+		return n, las.strictErr
+	}
+	if err != nil {
+		return n, err
+	}
+	// lines of data section not stored
+	las.rows = las.rows[:m]
+	if len(sectionLine) > 0 { // las 3.0, sections after main data section loaded as header
+		start := len(las.rows)
+		las.rows = append(las.rows, sectionLine)
+		las.ReadRows()
+		las.loadHeader(start)
+	}
+	if err = las.scanError(); err != nil {
+		return n, err
+	}
+	return n, las.checkData()
+}
+
+// OpenStream - read las file by LoadStream(), STEP determined by first lines of data section, see SetStreamHead()
+func (las *Las) OpenStream(fileName string, handler RowHandler) (int, error) {
+	var err error
+	las.File, err = os.Open(fileName)
+	if err != nil {
+		return 0, &LoadError{File: fileName, Err: ErrRead, Cause: err} //FATAL error - file not exist
+	}
+	defer las.File.Close()
+	las.FileName = fileName
+	return las.LoadStream(las.File, handler)
+}
+
+// readHeaderRows - reads to buffer 'rows' lines before main data section, line with title of data section also stored
+func (las *Las) readHeaderRows() int {
+	for las.scanner.Scan() {
+		s := las.scanner.Text()
+		las.rows = append(las.rows, s)
+		if t := strings.TrimSpace(s); (len(t) > 1) && (t[0] == '~') {
+			if group, kind := sectionTitle30(t); (group == "") && (kind == 'A') {
+				break
+			}
+		}
+	}
+	return len(las.rows)
+}
+
+// streamHeadSize - default number of lines of data section read before streaming, used to repair STRT and STEP
+const streamHeadSize = 100
+
+// SetStreamHead - set number of lines of data section read before streaming by LoadStream()
+// STRT, STEP and type of index determined by these lines, lines <= 0 - default 100 lines
+func (las *Las) SetStreamHead(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+	las.streamHead = lines
+}
+
+// getStreamHead - return number of lines specified by SetStreamHead() or default streamHeadSize
+func (las *Las) getStreamHead() int {
+	if las.streamHead == 0 {
+		return streamHeadSize
+	}
+	return las.streamHead
+}
+
+// readDataHead - reads to buffer 'rows' first lines of data section, reading stops at next section
+func (las *Las) readDataHead() {
+	for i := 0; (i < las.getStreamHead()) && las.scanner.Scan(); i++ {
+		s := las.scanner.Text()
+		las.rows = append(las.rows, s)
+		if t := strings.TrimSpace(s); (len(t) > 0) && (t[0] == '~') {
+			break
+		}
+	}
+}
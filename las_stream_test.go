@@ -0,0 +1,155 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	fp "path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var dStream = []string{
+	fp.Join("data/2.0/sample_2.0.las"),
+	fp.Join("data/more_20_warnings.las"),
+	fp.Join("data/1.2/sample_wrapped.las"),
+	fp.Join("data/tabulated_data.las"),
+	fp.Join("test_files/wrapped_broken.las"),
+	fp.Join("test_files/sample_3.0.las"),
+}
+
+// потоковое чтение должно давать те же данные и те же сообщения что и Open()
+func TestOpenStream(t *testing.T) {
+	for _, fn := range dStream {
+		las := NewLas()
+		n, err := las.Open(fn)
+		assert.Nil(t, err)
+
+		stream := NewLas()
+		i := 0
+		m, err := stream.OpenStream(fn, func(row *LasRow) error {
+			for j := range las.Logs {
+				assert.Equal(t, las.Logs[j].V[i], row.V[j], fmt.Sprintf("file '%s' row %d curve %d", fn, i, j))
+			}
+			assert.Equal(t, las.Logs[0].D[i], row.Dept())
+			i++
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, n, m, fn)
+		assert.Equal(t, n, i, fn)
+		assert.Equal(t, 0, stream.NumPoints())
+		assert.Equal(t, len(las.Logs), len(stream.Logs))
+		assert.ElementsMatch(t, las.Warnings, stream.Warnings, fn) // checker is map, order of header warnings not defined
+		assert.Equal(t, len(las.Groups), len(stream.Groups), fn)
+	}
+}
+
+func TestLoadStreamStop(t *testing.T) {
+	las := NewLas()
+	errStop := errors.New("stop")
+	n, err := las.OpenStream(fp.Join("data/2.0/sample_2.0.las"), func(row *LasRow) error {
+		if row.Dept() < 1669.9 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 2, n)
+
+	// after error of handler rest of input not read
+	var sb strings.Builder
+	sb.WriteString("~V\nVERS. 2.0 :\nWRAP. NO :\n~W\nSTRT.M 1 :\nSTEP.M 1 :\nNULL. -999.25 :\n~C\nDEPT.M :\nA. :\n~A\n")
+	for i := 1; i <= 100000; i++ {
+		fmt.Fprintf(&sb, "%d %d\n", i, i)
+	}
+	src := &countReader{r: strings.NewReader(sb.String())}
+	n, err = NewLas().LoadStream(src, func(row *LasRow) error {
+		if row.Dept() >= 200 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 200, n)
+	assert.Less(t, src.n, sb.Len()/10)
+
+	_, err = las.LoadStream(nil, func(row *LasRow) error { return nil })
+	assert.NotNil(t, err)
+	_, err = NewLas().OpenStream(fp.Join("data/2.0/sample_2.0.las"), nil)
+	assert.NotNil(t, err)
+	_, err = NewLas().OpenStream("not_exist_file.las", func(row *LasRow) error { return nil })
+	assert.NotNil(t, err)
+}
+
+// countReader - count bytes read from r
+type countReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// spacing of depth changes after first 100 lines: by default STEP on streaming read taken from first lines
+func TestStreamHead(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("~V\nVERS. 2.0 :\nWRAP. NO :\n~W\nSTRT.M 1 :\nSTOP.M 180 :\nNULL. -999.25 :\nWELL. W1 :\n~C\nDEPT.M :\nA. :\n~A\n")
+	for d := 1.0; d <= 150; d++ {
+		fmt.Fprintf(&sb, "%g %g\n", d, d)
+	}
+	for d := 150.5; d <= 180; d += 0.5 {
+		fmt.Fprintf(&sb, "%g %g\n", d, d)
+	}
+	src := sb.String()
+	las := NewLas()
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, las.STEP()) // irregular index
+
+	handler := func(row *LasRow) error { return nil }
+	stream := NewLas()
+	m, err := stream.LoadStream(strings.NewReader(src), handler)
+	assert.Nil(t, err)
+	assert.Equal(t, n, m)
+	assert.Equal(t, 1.0, stream.STEP())
+	assert.Contains(t, stream.Warnings.ToString(), "STEP: 1 not match irregular index")
+
+	stream = NewLas()
+	stream.SetStreamHead(300)
+	m, err = stream.LoadStream(strings.NewReader(src), handler)
+	assert.Nil(t, err)
+	assert.Equal(t, n, m)
+	assert.Equal(t, 0.0, stream.STEP())
+	assert.ElementsMatch(t, las.Warnings, stream.Warnings)
+}
+
+// on streaming read lines with wrong number of columns and missing values only counted
+func TestStreamRepairs(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("~V\nVERS. 2.0 :\nWRAP. NO :\n~W\nSTRT.M 1 :\nSTOP.M 1000 :\nSTEP.M 1 :\nNULL. -999.25 :\nWELL. W1 :\n~C\nDEPT.M :\nA. :\nB. :\n~A\n")
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&sb, "%d %d\n", i, i)
+	}
+	src := sb.String()
+	las := NewLas()
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, len(las.Repairs))
+
+	stream := NewLas()
+	m, err := stream.LoadStream(strings.NewReader(src), func(row *LasRow) error { return nil })
+	assert.Nil(t, err)
+	assert.Equal(t, n, m)
+	assert.Equal(t, 1000, stream.columnErrs)
+	assert.Equal(t, 14, stream.columnLine)
+	assert.Equal(t, RepairLog{{RepairMissing, 15, "", "", "", "1000 missing values of data section set to NULL, first line: 15"}}, stream.Repairs)
+	assert.ElementsMatch(t, las.Warnings, stream.Warnings)
+}
@@ -0,0 +1,151 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// strict mode of load: first violation of las 2.0 standard is error
+
+package glasio
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SetStrict - set strict mode of load
+// in strict mode Load() and LoadStream() return *LoadError with ErrStrict on first violation of las 2.0 standard:
+// section ~V, ~W, ~C or ~A not exist, mandatory item of ~V or ~W not exist, section ~C has no curves,
+// unknown section, not numeric value of data section, number of values in line not equal to number of curves
+// error contains line and column of violation, for missing item line of section title
+// sections of las 3.0 files not checked
+func (las *Las) SetStrict(on bool) {
+	las.strict = on
+}
+
+// strictItems - mandatory items of sections ~V and ~W, any name of item is enough
+var strictItems = []struct {
+	section rune
+	names   []string
+}{
+	{'V', []string{"VERS"}},
+	{'V', []string{"WRAP"}},
+	{'W', []string{"STRT"}},
+	{'W', []string{"STOP"}},
+	{'W', []string{"STEP"}},
+	{'W', []string{"NULL"}},
+	{'W', []string{"COMP"}},
+	{'W', []string{"WELL"}},
+	{'W', []string{"FLD"}},
+	{'W', []string{"LOC"}},
+	{'W', []string{"PROV", "CNTY", "STAT", "CTRY"}},
+	{'W', []string{"SRVC"}},
+	{'W', []string{"DATE"}},
+	{'W', []string{"UWI", "API"}},
+}
+
+// isStdSection - return true if section defined by las 2.0: ~V, ~W, ~C, ~P, ~O, ~A
+func isStdSection(r rune) bool {
+	return strings.ContainsRune("VWCPOA", unicode.ToUpper(r))
+}
+
+// violation - store first violation of standard in strict mode, line and column from 1, 0 if unknown
+func (las *Las) violation(section, line, column int, format string, a ...interface{}) {
+	if !las.strict || (las.strictErr != nil) {
+		return
+	}
+	w := TWarning{Section: section}
+	las.strictErr = &LoadError{las.FileName, line, column, w.SectionName(), ErrStrict, fmt.Errorf(format, a...)}
+}
+
+// resetStrict - clear result of strict check of previous load
+func (las *Las) resetStrict() {
+	las.strictErr = nil
+	las.secLines = nil
+}
+
+// storeSectionLine - store line of section title, s - title of section
+func (las *Las) storeSectionLine(s string) {
+	if las.secLines == nil {
+		las.secLines = make(map[rune]int)
+	}
+	r := unicode.ToUpper(sectionLetter(s))
+	if _, ok := las.secLines[r]; !ok {
+		las.secLines[r] = las.currentLine
+	}
+	if !isStdSection(r) && (las.VERS() < 3.0) {
+		las.violation(lasSecIgnore, las.currentLine, strings.Index(las.rows[las.currentLine-1], "~")+1, "unknown section '%s'", s)
+	}
+}
+
+// strictHeader - in strict mode return first violation of standard found in header, nil if header is valid
+func (las *Las) strictHeader() error {
+	if !las.strict {
+		return nil
+	}
+	if las.VERS() < 3.0 {
+		for _, r := range "VWCA" {
+			if las.secLines[r] == 0 {
+				las.violation(sectionNumber(string(r)), 0, 0, "section ~%c not exist", r)
+			}
+		}
+	}
+	for _, it := range strictItems {
+		sec := las.VerSec
+		if it.section == 'W' {
+			sec = las.WelSec
+		}
+		found := false
+		for _, name := range it.names {
+			if _, ok := sec.Get(name); ok {
+				found = true
+			}
+		}
+		if !found {
+			las.violation(sectionNumber(string(it.section)), las.secLines[it.section], 0, "mandatory item %s not exist", strings.Join(it.names, " or "))
+		}
+	}
+	if len(las.Logs) == 0 {
+		las.violation(lasSecCurInfo, las.secLines['C'], 0, "section ~C contains no curves")
+	}
+	if las.strictErr != nil {
+		return las.strictErr
+	}
+	return nil
+}
+
+// dataLine - line of data section and its index in source
+type dataLine struct {
+	n int
+	s string
+}
+
+// fieldPosition - return line and column (from 1) of value j of current record of data section
+// position computed on lines of current record stored in recLines
+// if record contains less values, return position after last value, column 0 if value not found in line
+func (las *Las) fieldPosition(j int) (int, int) {
+	dlm := las.DLM()
+	line, column := las.currentLine+1, 0
+	for _, l := range las.recLines {
+		pos := 0
+		for _, f := range splitDataLine(strings.TrimSpace(l.s), dlm) {
+			i := strings.Index(l.s[pos:], f)
+			if i < 0 {
+				return l.n + 1, 0 // value changed by split: quoted string
+			}
+			if j == 0 {
+				return l.n + 1, pos + i + 1
+			}
+			j--
+			pos += i + len(f)
+		}
+		line, column = l.n+1, len(l.s)+1
+	}
+	return line, column
+}
+
+// dataViolation - store violation of standard in value j of current record of data section
+func (las *Las) dataViolation(j int, format string, a ...interface{}) {
+	if !las.strict {
+		return
+	}
+	line, column := las.fieldPosition(j)
+	las.violation(lasSecData, line, column, format, a...)
+}
@@ -0,0 +1,122 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var strictSrc = []string{
+	"~V", "VERS. 2.0 :", "WRAP. NO :",
+	"~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. -999.25 :", "COMP. C :", "WELL. W :", "FLD. F :", "LOC. L :",
+	"PROV. P :", "SRVC. S :", "DATE. 13-DEC-86 :", "UWI. 100 :",
+	"~C", "DEPT.M :", "A. :",
+	"~A", "1.0 10", "2.0 20", "3.0 30"}
+
+// strictLines - return source with line n (from 1) replaced by s, if s == "" line removed
+func strictLines(n int, s string) string {
+	lines := append([]string{}, strictSrc...)
+	if len(s) == 0 {
+		lines = append(lines[:n-1], lines[n:]...)
+	} else {
+		lines[n-1] = s
+	}
+	return strings.Join(lines, "\n")
+}
+
+type tStrict struct {
+	src     string
+	line    int
+	column  int
+	section string
+	msg     string
+}
+
+var dStrict = []tStrict{
+	{strictLines(22, "2.0  abc"), 22, 6, "~A", "line 22, column 6, section ~A: violation of las standard: value 'abc' not numeric"},
+	{strictLines(22, "2.0 20 5"), 22, 8, "~A", "line 22, column 8, section ~A: violation of las standard: line contains 3 values, expected: 2"},
+	{strictLines(22, " 2.0"), 22, 5, "~A", "line 22, column 5, section ~A: violation of las standard: line contains 1 values, expected: 2"},
+	{strictLines(22, "x 20"), 22, 1, "~A", "line 22, column 1, section ~A: violation of las standard: depth 'x' not numeric"},
+	{strictLines(17, "~X extra\n~C"), 17, 1, "", "line 17, column 1: violation of las standard: unknown section '~X extra'"},
+	{strictLines(13, "CTRY. C :"), 0, 0, "", ""},
+	{strictLines(16, "API. 100 :"), 0, 0, "", ""},
+	{strictLines(13, ""), 4, 0, "~W", "line 4, section ~W: violation of las standard: mandatory item PROV or CNTY or STAT or CTRY not exist"},
+	{strictLines(3, ""), 1, 0, "~V", "line 1, section ~V: violation of las standard: mandatory item WRAP not exist"},
+	{strings.Join(strictSrc[:19], "\n"), 0, 0, "~A", "section ~A: violation of las standard: section ~A not exist"},
+	{strings.Join(append(append([]string{}, strictSrc[:17]...), strictSrc[19:]...), "\n"), 17, 0, "~C", "line 17, section ~C: violation of las standard: section ~C contains no curves"},
+}
+
+func TestStrict(t *testing.T) {
+	for i, tmp := range dStrict {
+		las := NewLas()
+		las.SetStrict(true)
+		_, err := las.Load(strings.NewReader(tmp.src))
+		if len(tmp.msg) == 0 {
+			assert.Nil(t, err, i)
+			continue
+		}
+		var e *LoadError
+		if assert.True(t, errors.As(err, &e), i) {
+			assert.True(t, errors.Is(err, ErrStrict), i)
+			assert.Equal(t, tmp.line, e.Line, i)
+			assert.Equal(t, tmp.column, e.Column, i)
+			assert.Equal(t, tmp.section, e.Section, i)
+			assert.Equal(t, tmp.msg, e.Error(), i)
+		}
+		// not strict mode: file loaded with warnings
+		las = NewLas()
+		_, err = las.Load(strings.NewReader(tmp.src))
+		if tmp.section != "~C" {
+			assert.Nil(t, err, i)
+		}
+	}
+	// standard files
+	for _, fn := range []string{"data/2.0/sample_2.0.las", "data/2.0/sample_2.0_wrapped.las"} {
+		las := NewLas()
+		las.SetStrict(true)
+		_, err := las.Open(fn)
+		assert.Nil(t, err, fn)
+	}
+	las := NewLas()
+	las.SetStrict(true)
+	_, err := las.Open("data/2.0/sample_2.0_missing_strt.las")
+	assert.True(t, errors.Is(err, ErrStrict))
+	assert.Contains(t, err.Error(), "line 4, section ~W: violation of las standard: mandatory item STRT not exist")
+
+	// streaming read stopped on first violation, rows before it passed to handler
+	las = NewLas()
+	las.SetStrict(true)
+	n, err := las.LoadStream(strings.NewReader(strictLines(22, "2.0  abc")), func(row *LasRow) error { return nil })
+	assert.Equal(t, 1, n)
+	assert.True(t, errors.Is(err, ErrStrict))
+
+	// position of violation after lines of data read before streaming
+	lines := append([]string{}, strictSrc[:len(strictSrc)-3]...)
+	for i := 1; i <= 200; i++ {
+		lines = append(lines, fmt.Sprintf("%d %d", i, i))
+	}
+	lines[len(lines)-50] = "151  abc"
+	las = NewLas()
+	las.SetStrict(true)
+	n, err = las.LoadStream(strings.NewReader(strings.Join(lines, "\n")), func(row *LasRow) error { return nil })
+	assert.Equal(t, 150, n)
+	var e *LoadError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, len(lines)-49, e.Line)
+		assert.Equal(t, 6, e.Column)
+	}
+
+	// result of previous load not kept
+	las = NewLas()
+	las.SetStrict(true)
+	_, err = las.Load(strings.NewReader(strictLines(22, "2.0  abc")))
+	assert.True(t, errors.Is(err, ErrStrict))
+	las.SetStrict(false)
+	_, err = las.Load(strings.NewReader(strings.Join(strictSrc, "\n")))
+	assert.Nil(t, err)
+}
@@ -4,6 +4,7 @@ package glasio
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,7 +34,9 @@ var dSummaryCheck = []tSummaryCheck{
 	{fp.Join("data/barebones.las"), 2.0, "NO", 200, -999.25, 1.1, -999.25, "", 1, 2, true, 200.0, 201.1, 0, 0},
 	{fp.Join("data/6038187_v1.2.las"), 2.0, "NO", 0.05, 136.6, 0.05, -99999, "Scorpio E1", 9, 2732, false, 0.05, 136.6, 49.7650, -56.2750},
 	{fp.Join("data/6038187_v1.2_short.las"), 2.0, "NO", 0.05, 136.6, 0.05, -99999, "Scorpio E1", 9, 121, false, 12.0, 18.0, 101.78, 101.259},
-	{fp.Join("data/1001178549.las"), 2.0, "YES", 1783.5, 1784.5, 0.25, -999.25, "1-28", 27, 0, true, 0, 0, 0, 0},
+	{fp.Join("data/1001178549.las"), 2.0, "YES", 1783.5, 1784.5, 0.25, -999.25, "1-28", 27, 5, false, 1783.5, 1784.5, -999.25, -999.25},
+	{fp.Join("data/1.2/sample_wrapped.las"), 1.2, "YES", 910, 901, -0.125, -999.25, "ANY ET AL XX-XX-XX-XX", 36, 5, false, 910, 909.5, -999.25, -999.25},
+	{fp.Join("data/2.0/sample_2.0_wrapped.las"), 2.0, "YES", 910, 909.5, -0.125, -999.25, "ANY ET AL 12-34-12-34", 36, 2, false, 910, 909.875, -999.25, -999.25},
 	{fp.Join("data/alog.las"), 1.20, "NO", 0, 0, 0.05, -999.25, "", 9, 24, false, 0.00, 0.00, 0.00, 0.00},
 	{fp.Join("data/autodepthindex_F.las"), 1.20, "NO", 0, 100, 1, -999.25, "ANY ET AL OIL WELL #12", 2, 101, false, 0, 100, 0.730568506467, 0.959183036405},
 	{fp.Join("data/barebones2.las"), 2.0, "NO", -999.25, -999.25, -999.25, -999.25, "", 0, 0, true, 0, 0, 0, 0}, // step и null не правятся, отсутствует секция Curve, ошибка заголовка
@@ -53,7 +56,7 @@ var dSummaryCheck = []tSummaryCheck{
 	{fp.Join("data/missing_null.las"), 1.2, "NO", 1670, 1660, -0.125, -999.25, "ANY ET AL OIL WELL #12", 8, 3, false, 1670.0, 1669.75, -999.25, 123.45},
 	{fp.Join("data/missing_vers.las"), 2.0, "NO", 1670, 1660, -0.125, -999.25, "WELL", 8, 3, false, 1670.0, 1669.75, 123.45, 123.45},
 	{fp.Join("data/missing_wrap.las"), 1.2, "NO", 1670, 1660, -0.125, -999.25, "ANY ET AL OIL WELL #12", 8, 3, false, 1670.0, 1669.75, 123.45, 123.45},
-	{fp.Join("data/more_20_warnings.las"), 1.2, "NO", 0.0, 0.0, 1.0, -32768.0, "6", 6, 22, true, 1, 2.2e11, -32768.0, 186}, //in file STEP=0.0 but this incorrect, LoadHeader replace STEP to actual from data
+	{fp.Join("data/more_20_warnings.las"), 1.2, "NO", 0.0, 0.0, 0.0, -32768.0, "6", 6, 22, true, 1, 2.2e11, -32768.0, 186}, //in file STEP=0.0, index in data irregular, STEP stay 0.0
 	{fp.Join("data/no-data-section.las"), 1.2, "NO", 0.0, 0.0, -32768.0, -32768.0, "6", 31, 0, true, 0, 0, 0, 0},           //in file STEP=0.0 but this incorrect, data section contain incorrect step too, result step equal NULL
 	{fp.Join("data/sample_bracketed_units.las"), 1.2, "NO", 1670, 1660, -0.125, -999.25, "ANY ET AL OIL WELL #12", 8, 3, true, 1670.0, 1669.75, 123.45, 123.45},
 	{fp.Join("data/test-curve-sec-empty-mnemonic.las"), 1.2, "NO", 1670, 1669.75, -0.125, -999.25, "ANY ET AL OIL WELL #12", 9, 3, true, 1670.0, 1669.75, 123.45, 123.45},
@@ -143,9 +146,9 @@ func TestLasCheck(t *testing.T) {
 	s = lasLog.msgCurve.String(fp.Join("data/test-curve-sec-empty-mnemonic+.las"))
 	assert.Contains(t, s, "*input log: B 	 mnemonic:*")
 	assert.Contains(t, s, "input log: SP 	 mnemonic: SP")
-	assert.Contains(t, s, "*input log: -EL-58 	 mnemonic:*")
+	assert.Contains(t, s, "*input log: -EL-5 	 mnemonic:*")
 	s = lasLog.missMnemonic.String()
-	assert.Contains(t, s, "-EL-7")
+	assert.Contains(t, s, "-EL-:4")
 	assert.NotContains(t, s, "SP")
 
 	lasLog, err = LasDeepCheck(fp.Join("data/more_20_warnings.las"), fp.Join("data/mnemonic.ini"), fp.Join("data/dic.ini"))
@@ -153,7 +156,7 @@ func TestLasCheck(t *testing.T) {
 	assert.Nil(t, err)
 	s = lasLog.msgOpen.ToString()
 	assert.Contains(t, s, "STEP parameter equal 0")
-	assert.Contains(t, s, "__WRN__ STRT: 0.000 == STOP: 0.000")
+	assert.Contains(t, s, "STRT: 0.000 == STOP: 0.000")
 	s = lasLog.msgCheck.String()
 	assert.Empty(t, s)
 	s = lasLog.msgCurve.String(fp.Join("data/more_20_warnings.las"))
@@ -171,9 +174,66 @@ func TestLasCheck(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.NotNil(t, lasLog.errorOnOpen)
 
-	// случай если las файл WRAP
+	// случай если las файл WRAP, такие файлы читаются
 	lasLog = LasCheck(fp.Join("data/1.2/sample_wrapped.las"))
-	assert.Contains(t, lasLog.msgCheck.String(), "WRAP=YES")
+	assert.Empty(t, lasLog.msgCheck.String())
+	assert.Equal(t, 5, lasLog.readedNumPoints)
 	lasLog, _ = LasDeepCheck(fp.Join("data/1.2/sample_wrapped.las"), fp.Join("data/mnemonic.ini"), fp.Join("data/dic.ini"))
-	assert.Contains(t, lasLog.msgCheck.String(), "WRAP=YES")
+	assert.Empty(t, lasLog.msgCheck.String())
+}
+
+// wrapped file with short and overflowed records
+func TestWrappedRead(t *testing.T) {
+	las := NewLas()
+	n, err := las.Open(fp.Join("test_files/wrapped_broken.las"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 10.1, las.Logs[0].D[1])
+	assert.Equal(t, 4.0, las.Logs[4].V[0])
+	assert.Equal(t, 15.0, las.Logs[5].V[1]) // second record overflowed, last value ignored
+	assert.Equal(t, 22.0, las.Logs[2].V[2])
+	assert.Equal(t, las.NULL(), las.Logs[5].V[2]) // last record short, filled by NULL
+	s := las.Warnings.ToString()
+	assert.Contains(t, s, "wrapped record contains 7 values, expected: 6")
+	assert.Contains(t, s, "last wrapped record contains 3 values, expected: 6")
+}
+
+// wrapped record not complete in middle of data, line with only depth begins new record
+func TestWrappedShortRecord(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. YES :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "C. :",
+		"~A", "1.0", "10 11", "2.0", "20 21 22", "3.0", "30 31 32"}, "\n")
+	las := NewLas()
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []float64{1, 2, 3}, las.Logs[0].V)
+	assert.Equal(t, []float64{10, 20, 30}, las.Logs[1].V)
+	assert.Equal(t, []float64{-999.25, 22, 32}, las.Logs[3].V)
+	wrn := make([]TWarning, 0)
+	for _, w := range las.Warnings {
+		if w.Code == WarnWrapRecordLength {
+			wrn = append(wrn, w)
+		}
+	}
+	if assert.Equal(t, 1, len(wrn)) {
+		assert.Equal(t, 16, wrn[0].Line+1)
+		assert.Contains(t, wrn[0].Desc, "wrapped record contains 3 values, expected: 4")
+	}
+}
+
+// one value on last line of wrapped record is value, not depth of next record
+func TestWrappedSingleValue(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. YES :", "~W", "STRT.M 1.0 :", "STOP.M 2.0 :", "STEP.M 1.0 :", "NULL. -999.25 :", "WELL. W1 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "C. :",
+		"~A", "1.0", "10 11", "12", "2.0", "20 21", "22"}, "\n")
+	las := NewLas()
+	n, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []float64{1, 2}, las.Logs[0].V)
+	assert.Equal(t, []float64{12, 22}, las.Logs[3].V)
+	assert.Equal(t, 0, las.Warnings.Count(), las.Warnings.ToString())
 }
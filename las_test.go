@@ -4,8 +4,14 @@ package glasio
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 
 	fp "path/filepath"
@@ -29,7 +35,7 @@ func TestLasOpenSpeсial(t *testing.T) {
 	errMsg := err.Error()
 	errMsgs := []string{"open : The system cannot find the file specified.", "open : no such file or directory"}
 	for _, msg := range errMsgs {
-		if msg == errMsg {
+		if errMsg == "read error: "+msg { // error of os.Open() wrapped in LoadError
 			errBool = true
 		}
 	}
@@ -39,7 +45,8 @@ func TestLasOpenSpeсial(t *testing.T) {
 	//this decode not support, return error
 	assert.Equal(t, 0, n)
 	assert.NotNil(t, err, fmt.Sprintf("<TestLasOpenSpeсial> expect error not nil, got '%v'\n", err))
-	assert.Equal(t, "cpd: codepage not support encode/decode", err.Error())
+	assert.True(t, errors.Is(err, ErrDecode))
+	assert.Equal(t, "file 'data/utf-32be-bom.las': source can not be decoded: cpd: codepage not support encode/decode", err.Error())
 }
 
 // Проверка на достижение максимального количества варнингов
@@ -55,12 +62,13 @@ func TestReachingMaxAmountWarnings(t *testing.T) {
 	MaxWarningCount = 100
 	las = NewLas()
 	las.Open(fp.Join("data/more_20_warnings.las"))
-	assert.Equal(t, 38, las.Warnings.Count(), fmt.Sprintf("<TestReachingMaxAmountWarnings> on file '%s' wrong warning number: %d expected 38\n", las.FileName, las.Warnings.Count()))
+	// 40 warnings on read and 3 of checks of data: number of columns, monotony of depth, STOP
+	assert.Equal(t, 43, las.Warnings.Count(), fmt.Sprintf("<TestReachingMaxAmountWarnings> on file '%s' wrong warning number: %d expected 43\n", las.FileName, las.Warnings.Count()))
 	MaxWarningCount = saveMaxWarningCount
 
 	// SaveWarning() does not add to las.Warnings
 	las.SaveWarning(fp.Join("data/more_20_warnings.wrn"))
-	assert.Equal(t, 38, las.Warnings.Count(), fmt.Sprintf("<TestReachingMaxAmountWarnings> after las.SaveWarning() number warning changed: %d expected 38\n", las.Warnings.Count()))
+	assert.Equal(t, 43, las.Warnings.Count(), fmt.Sprintf("<TestReachingMaxAmountWarnings> after las.SaveWarning() number warning changed: %d expected 43\n", las.Warnings.Count()))
 
 	// test for error occur when SaveWarning() fails to write to the file
 	assert.NotNil(t, las.SaveWarning(""))
@@ -127,6 +135,37 @@ func TestGetStepFromData(t *testing.T) {
 	}
 }
 
+// STRT and STEP repaired from data when las loaded from reader and by streaming read
+func TestStrtStepFromReader(t *testing.T) {
+	for _, tmp := range dGetDataStrt[:2] {
+		b, err := ioutil.ReadFile(tmp.fn)
+		assert.Nil(t, err)
+		las := NewLas()
+		las.Load(bytes.NewReader(b))
+		assert.Equal(t, tmp.st, las.STRT(), tmp.fn)
+		las = NewLas()
+		las.LoadStream(bytes.NewReader(b), func(row *LasRow) error { return nil })
+		assert.Equal(t, tmp.st, las.STRT(), tmp.fn)
+	}
+	for _, tmp := range dGetDataStep {
+		b, _ := ioutil.ReadFile(tmp.fn)
+		las := NewLas()
+		las.Load(bytes.NewReader(b))
+		assert.Equal(t, tmp.st, las.STEP(), tmp.fn)
+		las = NewLas()
+		las.LoadStream(bytes.NewReader(b), func(row *LasRow) error { return nil })
+		assert.Equal(t, tmp.st, las.STEP(), tmp.fn)
+	}
+	// wrapped file, index taken from first line of each record
+	las := NewLas()
+	las.Load(strings.NewReader(strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. YES :", "~W", "STRT.M 0 :", "STOP.M 2.5 :", "STEP.M 0 :", "NULL. -999.25 :",
+		"~C", "DEPT.M :", "A. :", "B. :", "C. :", "~A",
+		"1.5", "1 2 3", "2.0", "4 5 6", "2.5", "7 8 9"}, "\n")))
+	assert.Equal(t, 0.5, las.STEP())
+	assert.Equal(t, 3, las.NumPoints())
+}
+
 func TestLasSetNull(t *testing.T) {
 	las := NewLas()
 	las.Open(fp.Join("data/expand_points_01.las"))
@@ -192,6 +231,43 @@ func TestLasSave(t *testing.T) {
 	}
 }
 
+type tInputCodepage struct {
+	fn   string
+	cp   cpd.IDCodePage
+	set  bool
+	well string
+}
+
+var dInputCodepage = []tInputCodepage{
+	{fp.Join("test_files/~866.las"), cpd.CP866, false, `Примерная-101 /"бис"`},
+	{fp.Join("test_files/~866.las"), cpd.CP866, true, `Примерная-101 /"бис"`},
+	{fp.Join("test_files/~1251.las"), cpd.CP1251, false, "Примерная-101 / бис"},
+	{fp.Join("test_files/~1251.las"), cpd.CP1251, true, "Примерная-101 / бис"},
+	{fp.Join("test_files/~koi8.las"), cpd.KOI8R, true, `Примерная-1001 /"бис"`},
+	{fp.Join("data/encodings_utf8.las"), cpd.UTF8, false, ""},
+}
+
+func TestInputCodepage(t *testing.T) {
+	for _, tmp := range dInputCodepage {
+		las := NewLas()
+		if tmp.set {
+			las.SetInputCodepage(tmp.cp)
+		}
+		_, err := las.Open(tmp.fn)
+		assert.Nil(t, err, tmp.fn)
+		assert.Equal(t, tmp.cp, las.InputCodepage(), tmp.fn)
+		if len(tmp.well) > 0 {
+			assert.Equal(t, tmp.well, las.WELL(), tmp.fn)
+		}
+	}
+	// explicit codepage not replaced by autodetect
+	las := NewLas()
+	las.SetInputCodepage(cpd.CP866)
+	las.Open(fp.Join("test_files/~1251.las"))
+	assert.Equal(t, cpd.CP866, las.InputCodepage())
+	assert.NotEqual(t, "Примерная-101 / бис", las.WELL())
+}
+
 func TestSetNullOnEmptyLas(t *testing.T) {
 	las := NewLas()
 	las.SetNull(-1000)
@@ -233,8 +309,11 @@ func TestLasChecker(t *testing.T) {
 		las := makeSampleLas(tmp.cp, tmp.null, tmp.strt, tmp.stop, tmp.step, tmp.well)
 		assert.NotEqual(t, 0, len(chkr))
 		for key, chk := range chkr {
-			checkRes := chk.do(chk, las)
-			assert.Equal(t, tmp.testsRes[key], checkRes.res, fmt.Sprintf("i:%d, r:%s", i, key))
+			if chk.Data {
+				continue // data of sample las not loaded
+			}
+			checkRes := chk.Do(chk, las)
+			assert.Equal(t, tmp.testsRes[key], checkRes.Res, fmt.Sprintf("i:%d, r:%s", i, key))
 		}
 	}
 }
@@ -244,18 +323,20 @@ func TestLasChecker2(t *testing.T) {
 
 	tmp := dCheckLas[0] // в данных одна ошиба, NULL=0
 	las := makeSampleLas(tmp.cp, tmp.null, tmp.strt, tmp.stop, tmp.step, tmp.well)
-	res := stdChecker.check(las)
+	res := stdChecker.Check(las)
 	assert.Equal(t, 1, len(res))
-	assert.Equal(t, res["NULL"].name, "NULL")
+	assert.Equal(t, res["NULL"].Name, "NULL")
 	assert.True(t, res.nullWrong(), fmt.Sprintf("%v", res.nullWrong()))
-	assert.Contains(t, res["NULL"].warning.String(), "__WRN__ NULL parameter equal 0")
+	assert.Contains(t, res["NULL"].Warning.String(), "NULL parameter equal 0")
+	assert.Equal(t, WarnNullZero, res["NULL"].Warning.Code)
+	assert.Equal(t, SeverityWarning, res["NULL"].Warning.Severity)
 	assert.False(t, res.stepWrong())
 
 	tmp = dCheckLas[4] // 4 ошибки NULL=0, START=STOP, STEP=0, WELL=''
 	las = makeSampleLas(tmp.cp, tmp.null, tmp.strt, tmp.stop, tmp.step, tmp.well)
-	res = stdChecker.check(las)
+	res = stdChecker.Check(las)
 	assert.Equal(t, 4, len(res))
-	assert.Equal(t, res["STEP"].name, "STEP")
+	assert.Equal(t, res["STEP"].Name, "STEP")
 	assert.Contains(t, res["WELL"].String(), "name: WELL,")
 	assert.True(t, res.stepWrong())
 	assert.True(t, res.nullWrong())
@@ -266,10 +347,131 @@ func TestLasChecker2(t *testing.T) {
 	assert.False(t, res.wrapWrong(), fmt.Sprintf("%v", res["WRAP"]))
 
 	las = makeSampleLas(cpd.CP866, -999.25, 0, 100, 0.2, "well") //правильные данные
-	res = stdChecker.check(las)                                  //StdChecker должен вернуть пустой слайс
+	res = stdChecker.Check(las)                                  //StdChecker должен вернуть пустой слайс
 	assert.Equal(t, 0, len(res))
 }
 
+func TestUserChecker(t *testing.T) {
+	checker := NewStdChecker()
+	checker.Add(NewCheck("WNAM", "~W", "WELL not match naming scheme", func(las *Las) bool {
+		return strings.HasPrefix(las.WELL(), "W-")
+	}))
+	checker.Add(NewCheck("STPM", "~W", "STEP must be 0.1 m", func(las *Las) bool {
+		return math.Abs(las.STEP()) == 0.1
+	}))
+	assert.True(t, checker.Disable("WELL"))
+	assert.False(t, checker.Disable("NONE"))
+	src := strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. 0 :",
+		"WELL. 12 :", "~C", "DEPT.M :", "A. :", "~A", "1.0 10", "2.0 20", "3.0 30"}, "\n")
+	las := NewLas()
+	las.SetChecker(checker)
+	_, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	codes := make([]WarningCode, 0, len(las.Warnings))
+	for _, w := range las.Warnings {
+		codes = append(codes, w.Code)
+	}
+	// user checks reported as standard, order of checks by name
+	assert.Equal(t, []WarningCode{WarnNullZero, "STPM", "WNAM"}, codes)
+	assert.Equal(t, "WELL not match naming scheme", las.Warnings[2].Desc)
+	assert.Equal(t, "~W", las.Warnings[2].SectionName())
+	assert.Equal(t, las.stdNull, las.NULL())
+
+	// disabled check not performed and not repair
+	checker.Disable("NULL")
+	checker.Enable("WELL")
+	las = NewLas()
+	las.SetChecker(checker)
+	las.Load(strings.NewReader(src))
+	assert.Equal(t, 0.0, las.NULL())
+	assert.Contains(t, las.Warnings.ToString(), "WELL not match naming scheme")
+	assert.NotContains(t, las.Warnings.ToString(), "NULL parameter equal 0")
+
+	// fatal error of user check returned from Load
+	errQuarantine := errors.New("well not registered")
+	checker.Add(Check{Name: "WREG", Section: "~W", Do: func(chk Check, las *Las) CheckRes {
+		ok := las.WELL() != "12"
+		return CheckRes{chk.Name, TWarning{Desc: "WELL not registered"}, errQuarantine, ok}
+	}})
+	las = NewLas()
+	las.SetChecker(checker)
+	_, err = las.Load(strings.NewReader(src))
+	assert.True(t, errors.Is(err, errQuarantine))
+	assert.Contains(t, las.Warnings.ToString(), "WELL not registered")
+
+	// several fatal errors: error of first check in order of names returned
+	errArchive := errors.New("well archived")
+	checker.Add(Check{Name: "WARC", Section: "~W", Do: func(chk Check, las *Las) CheckRes {
+		return CheckRes{chk.Name, TWarning{Desc: "WELL archived"}, errArchive, false}
+	}})
+	for i := 0; i < 20; i++ {
+		las = NewLas()
+		las.SetChecker(checker)
+		_, err = las.Load(strings.NewReader(src))
+		assert.True(t, errors.Is(err, errArchive))
+	}
+	checker.Disable("WARC")
+
+	// nil checker - standard checks
+	las = NewLas()
+	las.SetChecker(nil)
+	las.Load(strings.NewReader(src))
+	assert.Equal(t, las.stdNull, las.NULL())
+}
+
+func TestDataChecks(t *testing.T) {
+	// header consistent with data: no warnings
+	src := strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 4.0 :", "STEP.M 1.0 :", "NULL. -999.25 :",
+		"WELL. W1 :", "~C", "DEPT.M :", "A. :", "~A", "1.0 10", "2.0 -999.25", "3.0 30", "4.0 40"}, "\n")
+	las := NewLas()
+	las.Load(strings.NewReader(src))
+	assert.Equal(t, 0, las.Warnings.Count(), las.Warnings.ToString())
+
+	src = strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 5.0 :", "STEP.M 0.5 :", "NULL. -999.25 :",
+		"WELL. W1 :", "~C", "DEPT.M :", "A. :", "~A", "1.0 10", "2.0 -9999", "3.0 30 31", "2.0 20", "4.0 -9999"}, "\n")
+	las = NewLas()
+	_, err := las.Load(strings.NewReader(src))
+	assert.Nil(t, err)
+	res := make(map[WarningCode]TWarning)
+	for _, w := range las.Warnings {
+		res[w.Code] = w
+	}
+	assert.Equal(t, "STOP: 5 not equal to last depth: 4", res[WarnDataStop].Desc)
+	assert.Equal(t, 5, res[WarnDataStop].Line)
+	assert.Equal(t, "STEP: 0.5 not match irregular index with step: 1", res[WarnDataStep].Desc)
+	assert.Equal(t, 6, res[WarnDataStep].Line)
+	assert.Equal(t, "1 lines contain number of columns not equal to 2 curves, first line: 16", res[WarnDataColumns].Desc)
+	assert.Equal(t, "depth not strictly monotonic, wrong points: 1, first: 2 after 3", res[WarnDataMonotony].Desc)
+	assert.Equal(t, 16, res[WarnDataMonotony].Line)
+	assert.Equal(t, "data contain 2 values -9999, but NULL is -999.25", res[WarnDataNull].Desc)
+	assert.Equal(t, 14, res[WarnDataNull].Line)
+	assert.Equal(t, "~A", res[WarnDataNull].SectionName())
+	// on streaming read checks of data performed row by row
+	stream := NewLas()
+	_, err = stream.LoadStream(strings.NewReader(src), func(row *LasRow) error { return nil })
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, las.Warnings, stream.Warnings)
+
+	// descending log with duplicated first row: only one wrong point
+	src2 := strings.Join([]string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 5.0 :", "STOP.M 1.0 :", "STEP.M -1.0 :", "NULL. -999.25 :",
+		"WELL. W1 :", "~C", "DEPT.M :", "A. :", "~A", "5.0 10", "5.0 10", "4.0 20", "3.0 30", "2.0 40", "1.0 50"}, "\n")
+	las = NewLas()
+	las.Load(strings.NewReader(src2))
+	assert.Contains(t, las.Warnings.ToString(), "depth not strictly monotonic, wrong points: 1, first: 5 after 5")
+
+	// checks of data disabled by name, user check of data
+	checker := NewStdChecker()
+	for _, name := range []string{"DSTP", "DSTE", "DCOL", "DMON", "DNUL"} {
+		assert.True(t, checker.Disable(name))
+	}
+	checker.Add(NewDataCheck("NPTS", "~A", "less than 10 points", func(las *Las) bool { return las.NumPoints() >= 10 }))
+	las = NewLas()
+	las.SetChecker(checker)
+	las.Load(strings.NewReader(src))
+	assert.Contains(t, las.Warnings.ToString(), "less than 10 points")
+	assert.NotContains(t, las.Warnings.ToString(), "not strictly monotonic")
+}
+
 func BenchmarkSave1(b *testing.B) {
 	for _, tmp := range dSaveLas {
 		las := makeSampleLas(tmp.cp, tmp.null, tmp.strt, tmp.stop, tmp.step, tmp.well)
@@ -317,3 +519,262 @@ func TestSection(t *testing.T) {
 		assert.Equal(t, tmp.nCurvs, len(las.CurSec.params))
 	}
 }
+
+// сохранение в формате WRAP = YES, ширина строки 80
+func TestLasSaveWrapped(t *testing.T) {
+	las := NewLas()
+	n, err := las.Open(fp.Join("data/1.2/sample_wrapped.las"))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, n)
+	las.SetWrapWidth(80)
+	b, err := las.SaveToBuf(false)
+	assert.Nil(t, err)
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	inData := false
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "~A") {
+			inData = true
+			continue
+		}
+		if inData {
+			assert.LessOrEqual(t, len(sc.Text()), 80)
+		}
+	}
+	assert.Contains(t, string(b), "910.0000\n")
+
+	las2 := NewLas()
+	n, err = las2.Load(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, n)
+	assert.True(t, las2.IsWraped())
+	// STOP of sample_wrapped.las is 901, only this warning expected
+	assert.Equal(t, 1, las2.Warnings.Count(), las2.Warnings.ToString())
+	assert.Equal(t, WarnDataStop, las2.Warnings[0].Code)
+	assert.Equal(t, las.Logs[35].V[4], las2.Logs[35].V[4])
+	assert.Equal(t, las.Logs[0].D[4], las2.Logs[0].D[4])
+
+	las.SetWrapWidth(0)
+	b, _ = las.SaveToBuf(false)
+	assert.Contains(t, string(b), "WRAP.                          NO")
+}
+
+// запись WRAP = YES и повторное чтение, на последней строке записи одно значение
+func TestLasSaveWrappedRoundTrip(t *testing.T) {
+	lines := []string{"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 1.0 :", "NULL. -999.25 :", "WELL. W1 :", "~C", "DEPT.M :"}
+	for j := 1; j < 9; j++ {
+		lines = append(lines, fmt.Sprintf("C%d. :", j))
+	}
+	lines = append(lines, "~A")
+	for i := 1; i <= 3; i++ {
+		row := fmt.Sprintf("%d.0", i)
+		for j := 1; j < 9; j++ {
+			row += fmt.Sprintf(" %d.5", i*10+j)
+		}
+		lines = append(lines, row)
+	}
+	las := NewLas()
+	_, err := las.Load(strings.NewReader(strings.Join(lines, "\n")))
+	assert.Nil(t, err)
+	// 80 - 7 values on line and 1 on last, 11 and 20 - one value on line, 5 - increased to width of one value
+	for _, width := range []int{80, 20, 11, 5} {
+		las.SetWrapWidth(width)
+		b, err := las.SaveToBuf(false)
+		assert.Nil(t, err)
+		las2 := NewLas()
+		n, err := las2.Load(bytes.NewReader(b))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n, width)
+		assert.Equal(t, 0, las2.Warnings.Count(), las2.Warnings.ToString())
+		for j := range las.Logs {
+			assert.Equal(t, las.Logs[j].V, las2.Logs[j].V, width)
+		}
+	}
+}
+
+// чтение las 3.0 с группами данных, разделитель COMMA
+func TestLas30Read(t *testing.T) {
+	las := NewLas()
+	n, err := las.Open(fp.Join("test_files/sample_3.0.las"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 3.0, las.VERS())
+	assert.Equal(t, "COMMA", las.DLM())
+	assert.Equal(t, 1670.0, las.STRT())
+	assert.Equal(t, "ANY ET AL 12-34-12-34", las.WELL())
+	assert.Equal(t, 2, len(las.ParSec.params))
+	assert.Equal(t, 4, len(las.Logs))
+	assert.Equal(t, "F", las.Logs[1].Format)
+	assert.Equal(t, "SONIC TRANSIT TIME", las.Logs[1].Desc)
+	assert.Equal(t, 123.45, las.Logs[1].V[1])
+	assert.Equal(t, las.NULL(), las.Logs[1].V[2]) // empty value
+	assert.True(t, las.Logs[3].IsString())
+	assert.Equal(t, []string{"SAND", "SHALE", "LIME"}, las.Logs[3].S)
+	assert.Equal(t, 0, las.Warnings.Count(), las.Warnings.ToString())
+
+	assert.Equal(t, 2, len(las.Groups))
+	core := las.Group("core")
+	assert.NotNil(t, core)
+	assert.Equal(t, "Core", core.Name)
+	assert.Equal(t, "ROTARY", core.ParSec.params["C_SRS"].Val)
+	assert.Equal(t, 4, len(core.Curves))
+	assert.Equal(t, 2, core.NumPoints())
+	assert.Equal(t, 0.15, core.Curves[2].V[1])
+	assert.Equal(t, "shaly sand", core.Curves[3].S[1])
+	tops := las.Group("Tops")
+	assert.NotNil(t, tops)
+	assert.Equal(t, 1669.8, tops.Curves[0].D[1])
+	assert.Equal(t, "Mannville", tops.Curves[1].S[1])
+	assert.Nil(t, las.Group("Drilling"))
+}
+
+type tSectionTitle30 struct {
+	s     string
+	group string
+	kind  rune
+}
+
+var dSectionTitle30 = []tSectionTitle30{
+	{"~Version Information", "", 'V'},
+	{"~Well", "", 'W'},
+	{"~Curve", "", 'C'},
+	{"~A DEPT  DT", "", 'A'},
+	{"~Log_Parameter", "", 'P'},
+	{"~Log_Definition", "", 'C'},
+	{"~Log_Data | Log_Definition", "", 'A'},
+	{"~Core_Parameter", "Core", 'P'},
+	{"~Core_Definition", "Core", 'C'},
+	{"~Core_Data | Core_Definition", "Core", 'A'},
+	{"~Drilling_Data|Drilling_Definition", "Drilling", 'A'},
+	{"~Other", "", 'O'},
+	{"~Inclinometry_Info", "Inclinometry", 'O'},
+}
+
+func TestSectionTitle30(t *testing.T) {
+	for _, tmp := range dSectionTitle30 {
+		g, k := sectionTitle30(tmp.s)
+		assert.Equal(t, tmp.group, g, tmp.s)
+		assert.Equal(t, tmp.kind, k, tmp.s)
+	}
+}
+
+func TestSplitDataLine(t *testing.T) {
+	assert.Equal(t, []string{"1.0", "2", "sand stone", "3"}, splitDataLine(`1.0  2 "sand stone"   3`, "SPACE"))
+	assert.Equal(t, []string{"1.0", "", "sand stone"}, splitDataLine(`1.0,, "sand stone"`, "COMMA"))
+	assert.Equal(t, []string{"1.0", "2", "3"}, splitDataLine("1.0\t2\t 3", "TAB"))
+	assert.Equal(t, []string{"1.0", "sand, shaly", "3"}, splitDataLine(`1.0,"sand, shaly",3`, "COMMA"))
+	assert.Equal(t, []string{"1.0", "sand\tshaly", "sand stone"}, splitDataLine("1.0\t\"sand\tshaly\"\tsand stone", "TAB"))
+	assert.Equal(t, []string{"1.0", `5" casing`, ""}, splitDataLine(`1.0 "5"" casing" ""`, "SPACE"))
+}
+
+// malformed format specifier of las 3.0 written as plain F
+func TestFormatValue30(t *testing.T) {
+	las := NewLas()
+	c := &LasCurve{Index: 1, V: []float64{1.23456}}
+	for _, tmp := range []struct{ format, s string }{
+		{"F", "1.2346"}, {"F10.2", "      1.23"}, {"F.1", "1.2"}, {"F8", "  1.2346"}, {"E.2", "1.23E+00"}, {"I4", "   1"},
+		{"F10.4.2", "1.2346"}, {"F10.", "1.2346"}, {"F1%d", "1.2346"}, {"E-3", "1.2346"}, {"I4.x", "1.2346"},
+	} {
+		assert.Equal(t, tmp.s, las.formatValue30(c, 0, tmp.format), tmp.format)
+	}
+}
+
+// запись las 3.0 и повторное чтение
+func TestLas30Save(t *testing.T) {
+	las := NewLas()
+	_, err := las.Open(fp.Join("test_files/sample_3.0.las"))
+	assert.Nil(t, err)
+	assert.NotNil(t, las.SetSaveVersion(2.5))
+	assert.NotNil(t, las.SetSaveVersion(3.0, ";"))
+	las.Logs[3].S[0] = `sand, shaly "5"` // delimiter and quote inside string value
+	for _, dlm := range []string{"COMMA", "TAB", "SPACE"} {
+		assert.Nil(t, las.SetSaveVersion(3.0, dlm))
+		b, err := las.SaveToBuf(false)
+		assert.Nil(t, err)
+		las2 := NewLas()
+		n, err := las2.Load(bytes.NewReader(b))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n, dlm)
+		assert.Equal(t, 3.0, las2.VERS())
+		assert.Equal(t, dlm, las2.DLM())
+		assert.Equal(t, 0, las2.Warnings.Count(), las2.Warnings.ToString())
+		assert.Equal(t, "35.5000", las2.ParSec.params["BHT"].Val)
+		assert.Equal(t, las.WelSec.params["COMP"].Val, las2.WelSec.params["COMP"].Val)
+		assert.Equal(t, las.Logs[3].S, las2.Logs[3].S)
+		assert.Equal(t, las.Logs[1].V, las2.Logs[1].V)
+		assert.Equal(t, "S", las2.Logs[3].Format)
+		core := las2.Group("Core")
+		assert.NotNil(t, core)
+		assert.Equal(t, "grey sand", core.Curves[3].S[0])
+		assert.Equal(t, 0.125, core.Curves[2].V[0])
+		assert.Equal(t, "E", core.Curves[2].Format)
+		assert.Equal(t, "ROTARY", core.ParSec.params["C_SRS"].Val)
+		assert.Equal(t, "Mannville", las2.Group("Tops").Curves[1].S[1])
+	}
+
+	// las 2.0 with sections ~P and ~O converted to 3.0
+	las = NewLas()
+	_, err = las.Open(fp.Join("data/2.0/sample_2.0.las"))
+	assert.Nil(t, err)
+	assert.Nil(t, las.SetSaveVersion(3.0))
+	b, _ := las.SaveToBuf(false)
+	assert.Contains(t, string(b), "~Log_Parameter\n")
+	assert.Contains(t, string(b), "~Other\nNote: The logging tools became stuck at 625 metres causing the data")
+	las2 := NewLas()
+	n, err := las2.Load(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 8, len(las2.ParSec.params))
+	assert.Equal(t, las.ParSec.params["MUD"].Val, las2.ParSec.params["MUD"].Val)
+	assert.Equal(t, "MM", las2.ParSec.params["BS"].Unit)
+	assert.Equal(t, "BIT SIZE", las2.ParSec.params["BS"].Desc)
+	assert.Equal(t, 2, len(las2.OthSec.params))
+	assert.Equal(t, 105.6, las2.Logs[7].V[2])
+}
+
+// все параметры всех секций сохраняются в исходном порядке вместе с единицами измерения и описанием
+func TestLasSaveAllParams(t *testing.T) {
+	for _, fn := range []string{"data/2.0/sample_2.0.las", "data/1.2/sample_curve_api.las", "data/autodepthindex_F.las"} {
+		las := NewLas()
+		_, err := las.Open(fp.Join(fn))
+		assert.Nil(t, err, fn)
+		b, err := las.SaveToBuf(false)
+		assert.Nil(t, err, fn)
+		las2 := NewLas()
+		_, err = las2.Load(bytes.NewReader(b))
+		assert.Nil(t, err, fn)
+		for k, sec := range [][2]HeaderSection{{las.WelSec, las2.WelSec}, {las.ParSec, las2.ParSec}, {las.OthSec, las2.OthSec}} {
+			p1, p2 := sec[0].sortedParams(), sec[1].sortedParams()
+			assert.Equal(t, len(p1), len(p2), fn)
+			for i := 0; (i < len(p1)) && (i < len(p2)); i++ {
+				if k == 2 { // names of ~O lines are line numbers
+					assert.Equal(t, p1[i].Val, p2[i].Val, fn)
+					continue
+				}
+				assert.Equal(t, p1[i].Name, p2[i].Name, fn)
+				switch p1[i].Name {
+				case "STRT", "STOP", "STEP", "NULL": // numbers written by format
+					v1, _ := strconv.ParseFloat(p1[i].Val, 64)
+					v2, _ := strconv.ParseFloat(p2[i].Val, 64)
+					assert.Equal(t, v1, v2, fn+" "+p1[i].Name)
+				default:
+					assert.Equal(t, p1[i].Val, p2[i].Val, fn+" "+p1[i].Name)
+				}
+				assert.Equal(t, p1[i].Unit, p2[i].Unit, fn+" "+p1[i].Name)
+				assert.Equal(t, p1[i].Desc, p2[i].Desc, fn+" "+p1[i].Name)
+			}
+		}
+		for i := range las.Logs {
+			assert.Equal(t, las.Logs[i].Unit, las2.Logs[i].Unit, fn)
+			assert.Equal(t, las.Logs[i].Desc, las2.Logs[i].Desc, fn)
+		}
+	}
+	las := NewLas()
+	las.Open(fp.Join("data/2.0/sample_2.0.las"))
+	b, _ := las.SaveToBuf(false)
+	s := string(b)
+	assert.Contains(t, s, "\n COMP.   ANY OIL COMPANY INC.                       :COMPANY\n")
+	assert.Contains(t, s, "\n~Parameter information\n MUD  .           GEL CHEM                       : MUD TYPE\n")
+	assert.Contains(t, s, "\n~Other information\nNote: The logging tools became stuck")
+	assert.Contains(t, s, " DT.US/M 60 520 32 00                     :2 SONIC TRANSIT TIME\n")
+	assert.True(t, strings.Index(s, " COMP.") < strings.Index(s, " WELL.") && strings.Index(s, " WELL.") < strings.Index(s, " FLD ."))
+}
@@ -0,0 +1,169 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// units of measure and conversion of curve values
+
+package glasio
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// dimensions of units
+const (
+	DimLength       = "length"
+	DimTime         = "time"
+	DimResistivity  = "resistivity"
+	DimConductivity = "conductivity"
+	DimSlowness     = "slowness"
+	DimDensity      = "density"
+	DimFraction     = "fraction"
+	DimTemperature  = "temperature"
+	DimPressure     = "pressure"
+	DimVoltage      = "voltage"
+	DimGamma        = "gamma"
+)
+
+// Unit - unit of measure
+// value in base unit of dimension = value * Factor + Offset
+type Unit struct {
+	Name   string  // canonical name: OHMM, US/F, G/C3 ...
+	Dim    string  // dimension, units of one dimension can be converted to each other
+	Factor float64 // value of unit in base unit of dimension
+	Offset float64 // nonzero only for temperature
+}
+
+// UnitRegistry - known units and their aliases
+// names and aliases are case insensitive
+type UnitRegistry struct {
+	units map[string]Unit // key - upper case name or alias
+}
+
+// NewUnitRegistry - create registry with common well log units
+func NewUnitRegistry() *UnitRegistry {
+	r := &UnitRegistry{make(map[string]Unit)}
+	// length, base M
+	r.Add(Unit{"M", DimLength, 1, 0}, "METER", "METRE", "М")
+	r.Add(Unit{"F", DimLength, 0.3048, 0}, "FT", "FEET", "FOOT", "ФУТ")
+	r.Add(Unit{".1IN", DimLength, 0.00254, 0})
+	r.Add(Unit{"IN", DimLength, 0.0254, 0}, "INCH", "ДЮЙМ")
+	r.Add(Unit{"CM", DimLength, 0.01, 0}, "СМ")
+	r.Add(Unit{"MM", DimLength, 0.001, 0}, "ММ")
+	// time, base S
+	r.Add(Unit{"S", DimTime, 1, 0}, "SEC", "С")
+	r.Add(Unit{"MS", DimTime, 0.001, 0}, "MSEC", "МС")
+	r.Add(Unit{"MIN", DimTime, 60, 0}, "МИН")
+	r.Add(Unit{"H", DimTime, 3600, 0}, "HR", "HOUR", "Ч")
+	r.Add(Unit{"D", DimTime, 86400, 0}, "DAY", "СУТ")
+	// resistivity, base OHMM
+	r.Add(Unit{"OHMM", DimResistivity, 1, 0}, "OHM.M", "OHM-M", "OHM*M", "OHM_M", "ОММ", "ОМ*М", "ОМ.М", "ОМ-М")
+	// conductivity, base MMHO/M
+	r.Add(Unit{"MMHO/M", DimConductivity, 1, 0}, "MS/M", "MMHOS/M", "МСМ/М")
+	r.Add(Unit{"MHO/M", DimConductivity, 1000, 0}, "S/M", "СМ/М")
+	// slowness, base US/M
+	r.Add(Unit{"US/M", DimSlowness, 1, 0}, "USEC/M", "МКС/М")
+	r.Add(Unit{"US/F", DimSlowness, 1 / 0.3048, 0}, "US/FT", "USEC/F", "USEC/FT", "МКС/ФУТ")
+	// density, base G/C3
+	r.Add(Unit{"G/C3", DimDensity, 1, 0}, "G/CM3", "G/CC", "GR/CC", "GM/CC", "Г/СМ3", "Г/СМ^3")
+	r.Add(Unit{"K/M3", DimDensity, 0.001, 0}, "KG/M3", "КГ/М3", "КГ/М^3")
+	// fraction, base V/V
+	r.Add(Unit{"V/V", DimFraction, 1, 0}, "FRAC", "DEC", "M3/M3", "Д.ЕД.", "Д.ЕД", "ДОЛИ")
+	r.Add(Unit{"PU", DimFraction, 0.01, 0}, "%", "P.U.", "PERCENT")
+	// temperature, base DEGC
+	r.Add(Unit{"DEGC", DimTemperature, 1, 0}, "C", "°C", "DEG C", "ГРАД")
+	r.Add(Unit{"DEGF", DimTemperature, 5.0 / 9.0, -32 * 5.0 / 9.0}, "°F", "DEG F")
+	r.Add(Unit{"K", DimTemperature, 1, -273.15}, "DEGK")
+	// pressure, base KPA
+	r.Add(Unit{"KPA", DimPressure, 1, 0}, "КПА")
+	r.Add(Unit{"MPA", DimPressure, 1000, 0}, "МПА")
+	r.Add(Unit{"BAR", DimPressure, 100, 0}, "БАР")
+	r.Add(Unit{"ATM", DimPressure, 101.325, 0}, "АТМ")
+	r.Add(Unit{"PSI", DimPressure, 6.894757, 0})
+	// voltage, base MV
+	r.Add(Unit{"MV", DimVoltage, 1, 0}, "МВ")
+	r.Add(Unit{"V", DimVoltage, 1000, 0}, "В")
+	// gamma ray, base API
+	r.Add(Unit{"API", DimGamma, 1, 0}, "GAPI", "API-GR")
+	return r
+}
+
+// StdUnits - registry of units used by LasCurve.ConvertUnit(), you can add your own units
+var StdUnits = NewUnitRegistry()
+
+func unitKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// Add - add unit and its aliases to registry, existing unit with the same name or alias replaced
+func (r *UnitRegistry) Add(u Unit, aliases ...string) {
+	r.units[unitKey(u.Name)] = u
+	for _, a := range aliases {
+		r.units[unitKey(a)] = u
+	}
+}
+
+// Lookup - return unit by name or alias, false if unit unknown
+func (r *UnitRegistry) Lookup(name string) (Unit, bool) {
+	u, ok := r.units[unitKey(name)]
+	return u, ok
+}
+
+// lookupDim - return unit by name or alias if it has dimension dim
+func (r *UnitRegistry) lookupDim(name, dim string) (Unit, bool) {
+	u, ok := r.Lookup(name)
+	return u, ok && (u.Dim == dim)
+}
+
+// Converter - return function converting values from unit to unit
+// error if unit unknown or units have different dimensions
+func (r *UnitRegistry) Converter(from, to string) (func(float64) float64, error) {
+	uf, ok := r.Lookup(from)
+	if !ok {
+		return nil, fmt.Errorf("unknown unit '%s'", from)
+	}
+	ut, ok := r.Lookup(to)
+	if !ok {
+		return nil, fmt.Errorf("unknown unit '%s'", to)
+	}
+	if uf.Dim != ut.Dim {
+		return nil, fmt.Errorf("unit '%s' (%s) cannot be converted to '%s' (%s)", from, uf.Dim, to, ut.Dim)
+	}
+	return func(v float64) float64 {
+		return roundUnit(((v*uf.Factor + uf.Offset) - ut.Offset) / ut.Factor)
+	}, nil
+}
+
+// Convert - convert value from unit to unit
+func (r *UnitRegistry) Convert(v float64, from, to string) (float64, error) {
+	f, err := r.Converter(from, to)
+	if err != nil {
+		return v, err
+	}
+	return f(v), nil
+}
+
+// roundUnit - remove noise of float arithmetic after convert, value rounded to 12 significant digits
+func roundUnit(v float64) float64 {
+	if (v == 0) || math.IsInf(v, 0) || math.IsNaN(v) {
+		return v
+	}
+	p := math.Pow(10, 11-math.Floor(math.Log10(math.Abs(v))))
+	return math.Round(v*p) / p
+}
+
+// ConvertUnit - convert values of curve to unit using StdUnits, unit of curve set to unit
+// values equal to null not changed, index of curve (D) not changed, to convert index use Las.ConvertIndex()
+func (o *LasCurve) ConvertUnit(unit string, null float64) error {
+	f, err := StdUnits.Converter(o.Unit, unit)
+	if err != nil {
+		return fmt.Errorf("curve '%s': %v", o.Name, err)
+	}
+	for i, v := range o.V {
+		if v != null {
+			o.V[i] = f(v)
+		}
+	}
+	o.Unit = unit
+	return nil
+}
@@ -0,0 +1,77 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tUnitConvert struct {
+	v    float64
+	from string
+	to   string
+	res  float64
+	err  bool
+}
+
+var dUnitConvert = []tUnitConvert{
+	{10, "OHMM", "ohm.m", 10, false},
+	{10, "Ом*м", "OHMM", 10, false},
+	{100, "US/F", "us/m", 328.083989501, false},
+	{100, "мкс/м", "US/FT", 30.48, false},
+	{2.65, "G/C3", "K/M3", 2650, false},
+	{2650, "кг/м3", "g/cm3", 2.65, false},
+	{25, "PU", "V/V", 0.25, false},
+	{0.25, "v/v", "%", 25, false},
+	{100, "DEGC", "DEGF", 212, false},
+	{32, "DEGF", "DEGC", 0, false},
+	{0, "DEGC", "K", 273.15, false},
+	{1, "ATM", "KPA", 101.325, false},
+	{100, "F", "M", 30.48, false},
+	{1, "OHMM", "G/C3", 1, true},
+	{1, "XXX", "OHMM", 1, true},
+	{1, "OHMM", "", 1, true},
+}
+
+func TestUnitConvert(t *testing.T) {
+	for i, tmp := range dUnitConvert {
+		v, err := StdUnits.Convert(tmp.v, tmp.from, tmp.to)
+		assert.Equal(t, tmp.err, err != nil, fmt.Sprintf("test %d: %s -> %s", i, tmp.from, tmp.to))
+		assert.Equal(t, tmp.res, v, fmt.Sprintf("test %d: %s -> %s", i, tmp.from, tmp.to))
+	}
+}
+
+func TestUnitRegistryAdd(t *testing.T) {
+	r := NewUnitRegistry()
+	_, ok := r.Lookup("KFT")
+	assert.False(t, ok)
+	r.Add(Unit{"KFT", DimLength, 304.8, 0}, "КФУТ")
+	u, ok := r.Lookup("кфут")
+	assert.True(t, ok)
+	assert.Equal(t, "KFT", u.Name)
+	v, err := r.Convert(1, "KFT", "F")
+	assert.Nil(t, err)
+	assert.Equal(t, 1000.0, v)
+	// std registry not changed
+	_, ok = StdUnits.Lookup("KFT")
+	assert.False(t, ok)
+}
+
+func TestCurveConvertUnit(t *testing.T) {
+	las := NewLas()
+	las.Open("test_files/sample_time.las")
+	c := &las.Logs[2]
+	assert.Equal(t, "DEGC", c.Unit)
+	c.V[1] = las.NULL()
+	assert.Nil(t, c.ConvertUnit("DEGF", las.NULL()))
+	assert.Equal(t, "DEGF", c.Unit)
+	assert.Equal(t, 113.18, c.V[0])
+	assert.Equal(t, las.NULL(), c.V[1])
+	assert.Equal(t, 0.0, c.D[0])
+	assert.NotNil(t, c.ConvertUnit("OHMM", las.NULL()))
+	assert.Equal(t, "DEGF", c.Unit)
+	assert.Equal(t, 113.18, c.V[0])
+}
@@ -1,7 +1,6 @@
 package glasio
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
@@ -54,13 +53,13 @@ func makeSampleLas(
 	} else {
 		las = NewLas(cp)
 	}
-	las.VerSec.params["VERS"] = HeaderParam{"2.0", "VERS", "", "", "", "glasio (c) softlandia@gmail.com", 2}
-	las.VerSec.params["WRAP"] = HeaderParam{"NO", "WRAP", "", "", "", "one line per depth step", 3}
-	las.WelSec.params["NULL"] = HeaderParam{strconv.FormatFloat(null, 'f', -1, 64), "NULL", "", "", "", "null value", 5}
-	las.WelSec.params["STRT"] = HeaderParam{strconv.FormatFloat(strt, 'f', -1, 64), "STRT", "", "", "", "first index value", 6}
-	las.WelSec.params["STOP"] = HeaderParam{strconv.FormatFloat(stop, 'f', -1, 64), "STOP", "", "", "", "last index value", 7}
-	las.WelSec.params["STEP"] = HeaderParam{strconv.FormatFloat(step, 'f', -1, 64), "STEP", "", "", "", "step of index", 8}
-	las.WelSec.params["WELL"] = HeaderParam{well, "WELL", "", "", "", "well", 9}
+	las.VerSec.params["VERS"] = HeaderParam{Val: "2.0", Name: "VERS", Desc: "glasio (c) softlandia@gmail.com", lineNo: 2}
+	las.VerSec.params["WRAP"] = HeaderParam{Val: "NO", Name: "WRAP", Desc: "one line per depth step", lineNo: 3}
+	las.WelSec.params["NULL"] = HeaderParam{Val: strconv.FormatFloat(null, 'f', -1, 64), Name: "NULL", Desc: "null value", lineNo: 5}
+	las.WelSec.params["STRT"] = HeaderParam{Val: strconv.FormatFloat(strt, 'f', -1, 64), Name: "STRT", Desc: "first index value", lineNo: 6}
+	las.WelSec.params["STOP"] = HeaderParam{Val: strconv.FormatFloat(stop, 'f', -1, 64), Name: "STOP", Desc: "last index value", lineNo: 7}
+	las.WelSec.params["STEP"] = HeaderParam{Val: strconv.FormatFloat(step, 'f', -1, 64), Name: "STEP", Desc: "step of index", lineNo: 8}
+	las.WelSec.params["WELL"] = HeaderParam{Val: well, Name: "WELL", Desc: "well", lineNo: 9}
 
 	curve := NewLasCurve("DEPT.m :", las)
 	curve.D = append(curve.D, 1.0, 1.1, 1.2, 1.3, 1.4)
@@ -85,8 +84,8 @@ func LoadLasHeader(fileName string) (*Las, error) {
 	defer iFile.Close()
 	las.File = iFile
 	las.FileName = fileName
-	las.Reader, err = cpd.NewReader(las.File)
-	las.scanner = bufio.NewScanner(las.Reader)
+	las.Reader, err = las.newReader(las.File)
+	las.scanner = las.newScanner()
 	if err != nil {
 		return nil, err
 	}
@@ -105,9 +104,6 @@ func LasCheck(filename string) *Logger {
 	lasLog.readedNumPoints = n
 	lasLog.errorOnOpen = err
 	lasLog.msgOpen = las.Warnings
-	if las.IsWraped() {
-		lasLog.msgCheck = append(lasLog.msgCheck, lasLog.msgCheck.msgFileIsWraped(filename))
-	}
 	if las.NumPoints() == 0 {
 		lasLog.msgCheck = append(lasLog.msgCheck, lasLog.msgCheck.msgFileNoData(filename))
 	}
@@ -0,0 +1,406 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// types and functions for las 3.0
+
+package glasio
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// LasGroup - data group of las 3.0
+// group consists of sections with same prefix: ~Core_Parameter, ~Core_Definition, ~Core_Data
+// main log group (~Log_* or ~Parameter, ~Curve, ~A) not stored in LasGroup, it is in Las.ParSec, Las.CurSec and Las.Logs
+type LasGroup struct {
+	Name   string        // name of group as in file: Core, Tops, Drilling...
+	ParSec HeaderSection // section ~Name_Parameter
+	DefSec HeaderSection // section ~Name_Definition
+	Curves LasCurves     // curves defined in ~Name_Definition, data read from ~Name_Data
+	lines  []lineRange   // source lines of data rows, used on lossless save
+}
+
+// NewLasGroup - create new empty data group
+func NewLasGroup(name string) *LasGroup {
+	g := new(LasGroup)
+	g.Name = name
+	g.ParSec = NewParSection()
+	g.DefSec = NewCurSection()
+	g.Curves = make(LasCurves, 0)
+	return g
+}
+
+// NumPoints - return number of data rows read to group
+func (g *LasGroup) NumPoints() int {
+	if len(g.Curves) == 0 {
+		return 0
+	}
+	return len(g.Curves[0].D)
+}
+
+// Group - return data group of las 3.0 by name, name is case insensitive
+// return nil if group not exist
+func (las *Las) Group(name string) *LasGroup {
+	for _, g := range las.Groups {
+		if strings.EqualFold(g.Name, name) {
+			return g
+		}
+	}
+	return nil
+}
+
+// group - return existing group or add new
+func (las *Las) group(name string) *LasGroup {
+	g := las.Group(name)
+	if g == nil {
+		g = NewLasGroup(name)
+		las.Groups = append(las.Groups, g)
+	}
+	return g
+}
+
+// sectionTitle30 - parse title of las 3.0 section
+// "~Core_Data | Core_Definition" -> "Core", 'A'
+// "~Log_Definition" -> "", 'C'
+// "~Curve" -> "", 'C'
+// group - name of group, empty for main log group and sections ~Version, ~Well, ~Other
+// kind - type of section: 'V', 'W', 'P', 'C', 'A', 'O'
+func sectionTitle30(s string) (group string, kind rune) {
+	title := strings.TrimSpace(strings.TrimPrefix(s, "~"))
+	if i := strings.Index(title, "|"); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if fields := strings.Fields(title); len(fields) > 0 {
+		title = fields[0]
+	}
+	if i := strings.LastIndex(title, "_"); i > 0 {
+		group = title[:i]
+		suffix := strings.ToUpper(title[i+1:])
+		switch {
+		case strings.HasPrefix(suffix, "PAR"):
+			kind = 'P'
+		case strings.HasPrefix(suffix, "DEF"):
+			kind = 'C'
+		case strings.HasPrefix(suffix, "DAT"):
+			kind = 'A'
+		default:
+			kind = 'O'
+		}
+		if strings.EqualFold(group, "Log") {
+			group = ""
+		}
+		return group, kind
+	}
+	if len(title) == 0 {
+		return "", 'O'
+	}
+	switch r := rune(strings.ToUpper(title)[0]); r {
+	case 'V', 'W', 'P', 'C', 'A':
+		return "", r
+	}
+	return "", 'O'
+}
+
+// section30 - select section of las 3.0 by title
+// data sections are processed at once: main data section skipped, *m set to first line of it (LoadDataSec read it later),
+// data section of other group read to group
+// returns section to store next parameters and data group, group is nil for main log group
+func (las *Las) section30(s string, m *int) (HeaderSection, *LasGroup) {
+	group, kind := sectionTitle30(s)
+	if group == "" {
+		if kind == 'A' {
+			if *m < 0 {
+				*m = las.currentLine
+			}
+			las.skipSection()
+			return las.OthSec, nil
+		}
+		return las.section(kind), nil
+	}
+	g := las.group(group)
+	switch kind {
+	case 'P':
+		return g.ParSec, g
+	case 'C':
+		return g.DefSec, g
+	case 'A':
+		g.loadData(las)
+		return las.OthSec, nil
+	}
+	return las.OthSec, nil
+}
+
+// skipSection - move currentLine to next section
+func (las *Las) skipSection() {
+	for las.currentLine < len(las.rows) {
+		s := strings.TrimSpace(las.rows[las.currentLine])
+		if (len(s) > 0) && (s[0] == '~') {
+			return
+		}
+		las.currentLine++
+	}
+}
+
+// addCurve - make new curve from string of section ~Name_Definition and store to group
+func (g *LasGroup) addCurve(s string, las *Las) {
+	lc := NewLasCurve(s, las)
+	lc.Name = g.Curves.UniqueName(lc.IName)
+	lc.Index = len(g.Curves)
+	lc.parseFormat()
+	las.warnRenamed(lc)
+	g.Curves = append(g.Curves, lc)
+}
+
+// loadData - read data section of group, stop on next section
+func (g *LasGroup) loadData(las *Las) {
+	dlm := las.DLM()
+	for las.currentLine < len(las.rows) {
+		line := strings.TrimSpace(las.rows[las.currentLine])
+		if (len(line) > 0) && (line[0] == '~') {
+			return
+		}
+		las.currentLine++
+		if isIgnoredLine(line) {
+			continue
+		}
+		g.storeDataRow(las, splitDataLine(line, dlm))
+	}
+}
+
+// storeDataRow - store one row of data to curves of group
+// first column is index, if index not numeric it set to NULL, row not ignored
+func (g *LasGroup) storeDataRow(las *Las, fields []string) {
+	n := len(g.Curves)
+	if n == 0 {
+		las.addWarning(newWarning(WarnGroupDefinition, lasSecData, las.currentLine, "group '%s' has no definition, data line ignored", g.Name))
+		return
+	}
+	if len(fields) != n {
+		las.addWarning(newWarning(WarnDataColumnCount, lasSecData, las.currentLine, "group '%s' line contains %d columns, expected: %d", g.Name, len(fields), n))
+	}
+	g.lines = append(g.lines, lineRange{las.currentLine, las.currentLine})
+	dept := las.NULL()
+	if (len(fields) > 0) && !g.Curves[0].IsString() {
+		dept, _ = las.parseDataValue(fields[0])
+	}
+	for j := range g.Curves {
+		s := ""
+		if j < len(fields) {
+			s = fields[j]
+		}
+		c := &g.Curves[j]
+		c.D = append(c.D, dept)
+		if c.IsString() {
+			c.S = append(c.S, s)
+			c.V = append(c.V, las.NULL())
+			continue
+		}
+		v, err := las.parseDataValue(s)
+		if err != nil {
+			las.addWarning(newWarning(WarnDataNotNumeric, lasSecData, las.currentLine, "group '%s' error convert string: '%s' to number, set to NULL", g.Name, s))
+		}
+		c.V = append(c.V, v)
+	}
+}
+
+// splitDataLine - split line of data section to values
+// dlm - delimiter from parameter DLM of section ~Version: SPACE, COMMA, TAB
+// values may be quoted: "sand stone", "sand, shaly", quote inside quoted value doubled: "5"" casing"
+func splitDataLine(line, dlm string) []string {
+	switch dlm {
+	case "COMMA":
+		return splitQuoted(line, ',')
+	case "TAB":
+		return splitQuoted(line, '\t')
+	}
+	if !strings.ContainsRune(line, '"') {
+		return strings.Fields(line)
+	}
+	return splitQuoted(line, ' ')
+}
+
+// splitQuoted - split line by separator sep, text in quotes is one value, quotes removed, doubled quote in quotes is quote
+// sep ' ' - values separated by any number of spaces and tabs
+// for other separators spaces around value ignored, empty value kept: "1,,2" - 3 values
+func splitQuoted(line string, sep rune) []string {
+	fields := make([]string, 0)
+	var sb strings.Builder
+	space := "" // spaces after value, written only if value continued
+	quoted, started, closed := false, false, false
+	for _, r := range line {
+		afterQuote := closed
+		closed = false
+		switch {
+		case r == '"':
+			if afterQuote {
+				sb.WriteRune('"') // doubled quote inside quoted value: "5"" casing"
+			}
+			quoted = !quoted
+			closed = !quoted
+			started = true
+			sb.WriteString(space)
+			space = ""
+		case quoted:
+			sb.WriteRune(r)
+		case (r == sep) || ((sep == ' ') && (r == '\t')):
+			if started || (sep != ' ') {
+				fields = append(fields, sb.String())
+			}
+			sb.Reset()
+			space = ""
+			started = false
+		case (r == ' ') || (r == '\t'):
+			if started {
+				space += string(r)
+			}
+		default:
+			sb.WriteString(space)
+			space = ""
+			sb.WriteRune(r)
+			started = true
+		}
+	}
+	if started || (sep != ' ') {
+		fields = append(fields, sb.String())
+	}
+	return fields
+}
+
+// save30 - write las 3.0 file to buffer
+// main log group written to ~Log_Parameter, ~Log_Definition, ~Log_Data, other groups from Las.Groups after it
+func (las *Las) save30(b *bytes.Buffer, useMnemonic bool) {
+	fmt.Fprint(b, _LasFirstLine)
+	fmt.Fprintf(b, _LasVersion, 3.0)
+	fmt.Fprint(b, _LasWrap)
+	fmt.Fprintf(b, _LasDlm, las.oDLM)
+	las.saveVerSec(b)
+	las.saveWelSec(b)
+	las.saveGroup30(b, "Log", las.ParSec, las.Logs, useMnemonic)
+	for _, g := range las.Groups {
+		las.saveGroup30(b, g.Name, g.ParSec, g.Curves, false)
+	}
+	las.saveOthSec(b, "~Other\n")
+}
+
+// saveGroup30 - write parameter, definition and data sections of one group
+func (las *Las) saveGroup30(b *bytes.Buffer, name string, parSec HeaderSection, curves LasCurves, useMnemonic bool) {
+	if len(parSec.params) > 0 {
+		fmt.Fprintf(b, _LasGroupPar, name)
+		for _, p := range parSec.sortedParams() {
+			fmt.Fprintf(b, _LasParamLine, p.saveName(), p.Unit, p.Val, p.Desc)
+		}
+	}
+	fmt.Fprintf(b, _LasGroupDef, name)
+	formats := formats30(curves)
+	for i, c := range curves {
+		if useMnemonic && (len(c.Mnemonic) > 0) {
+			c.Name = c.Mnemonic
+		}
+		fmt.Fprintf(b, _LasParamLine30, c.saveName(), unitField(c.Unit), c.API, c.Desc, formats[i])
+	}
+	fmt.Fprintf(b, _LasGroupData, name, name)
+	if len(curves) == 0 {
+		return
+	}
+	for i := range curves[0].D {
+		las.saveRecord30(b, curves, formats, i, las.oDLM)
+	}
+}
+
+// formats30 - return format specifiers of curves, F if format not specified
+func formats30(curves LasCurves) []string {
+	formats := make([]string, len(curves))
+	for i, c := range curves {
+		formats[i] = c.Format
+		if len(formats[i]) == 0 {
+			formats[i] = "F"
+		}
+	}
+	return formats
+}
+
+// saveRecord30 - write one line of data section of las 3.0, values separated by dlm: SPACE, COMMA, TAB
+func (las *Las) saveRecord30(b *bytes.Buffer, curves LasCurves, formats []string, i int, dlm string) {
+	sep := " "
+	switch dlm {
+	case "COMMA":
+		sep = ", "
+	case "TAB":
+		sep = "\t"
+	}
+	for j := range curves {
+		if j > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(las.formatValue30(&curves[j], i, formats[j]))
+	}
+	b.WriteString("\n")
+}
+
+// formatSpec30 - return width and precision of format specifier without first letter: "10.4" -> "10", "4"
+// precision by default "4", ok false if specifier is not digits with optional point and digits
+func formatSpec30(spec string) (w, d string, ok bool) {
+	w, d = spec, "4"
+	if k := strings.IndexByte(spec, '.'); k >= 0 {
+		w, d = spec[:k], spec[k+1:]
+		if !isDigits(d) {
+			return "", "4", false
+		}
+	}
+	if (len(w) > 0) && !isDigits(w) {
+		return "", "4", false
+	}
+	return w, d, true
+}
+
+// isDigits - return true if s not empty and contains only digits 0..9
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0') || (s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatValue30 - return value of curve c at index i as string according format specifier
+// F - fixed point, Fw.d - width and precision, E - exponential, I - integer, strings quoted if necessary
+func (las *Las) formatValue30(c *LasCurve, i int, format string) string {
+	if c.IsString() {
+		s := ""
+		if i < len(c.S) {
+			s = c.S[i]
+		}
+		if (len(s) == 0) || strings.ContainsAny(s, " \t,\"") {
+			s = "\"" + strings.ReplaceAll(s, "\"", "\"\"") + "\"" // quote inside value doubled
+		}
+		return s
+	}
+	v := c.V[i]
+	if c.Index == 0 {
+		v = c.D[i]
+		if (len(las.Logs) > 0) && (c == &las.Logs[0]) && (las.IndexType() == IndexDateTime) {
+			return las.formatIndex(v, "")
+		}
+	}
+	f := strings.ToUpper(format)
+	if (len(f) > 1) && (f[0] == 'A') {
+		f = f[1:] // array of numbers
+	}
+	w, d, ok := formatSpec30(f[1:])
+	if !ok { // malformed specifier written as plain F
+		return fmt.Sprintf("%.4f", v)
+	}
+	switch f[0] {
+	case 'E':
+		return fmt.Sprintf("%"+w+"."+d+"E", v)
+	case 'I':
+		return fmt.Sprintf("%"+w+"d", int64(math.Round(v)))
+	}
+	return fmt.Sprintf("%"+w+"."+d+"f", v)
+}
@@ -2,9 +2,13 @@ package glasio
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -13,22 +17,122 @@ const (
 	directOnWrite = 2
 )
 
+// Severity - severity of warning
+type Severity int
+
+const (
+	// SeverityInfo - information, nothing changed
+	SeverityInfo Severity = iota
+	// SeverityWarning - violation of standard, value repaired or ignored, load continued
+	SeverityWarning
+	// SeverityError - file or its section can not be read
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityError:
+		return "error"
+	}
+	return "warning"
+}
+
+// MarshalText - severity written to json as string: info, warning, error
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// WarningCode - stable code of kind of warning, used to group and filter warnings
+type WarningCode string
+
+// codes of warnings
+const (
+	WarnUndefined        WarningCode = ""
+	WarnLimit            WarningCode = "WARNING_LIMIT"       // maximum count of warnings reached
+	WarnWrapInvalid      WarningCode = "WRAP_INVALID"        // WRAP not YES or NO
+	WarnCurveSecEmpty    WarningCode = "CURVE_SECTION_EMPTY" // section ~C is empty
+	WarnCurveDef         WarningCode = "CURVE_DEF"           // ambiguous line of section ~C
+	WarnCurveParam       WarningCode = "CURVE_PARAM"         // line of section ~C not read
+	WarnDuplicateCurve   WarningCode = "DUPLICATE_CURVE"     // duplicated name of curve, curve renamed
+	WarnDuplicateParam   WarningCode = "DUPLICATE_PARAM"     // duplicated name of parameter, parameter renamed
+	WarnStrtMissing      WarningCode = "STRT_MISSING"        // parameter STRT not exist
+	WarnStopMissing      WarningCode = "STOP_MISSING"        // parameter STOP not exist
+	WarnStepMissing      WarningCode = "STEP_MISSING"        // parameter STEP not exist
+	WarnStepZero         WarningCode = "STEP_ZERO"           // STEP equal 0
+	WarnNullZero         WarningCode = "NULL_ZERO"           // NULL equal 0
+	WarnStrtEqualStop    WarningCode = "STRT_EQUAL_STOP"     // STRT equal STOP
+	WarnWellEmpty        WarningCode = "WELL_EMPTY"          // WELL is empty
+	WarnStrtWrong        WarningCode = "STRT_WRONG"          // STRT not found in data, set to 0
+	WarnStepWrong        WarningCode = "STEP_WRONG"          // STEP can not be determined from data
+	WarnIndexIrregular   WarningCode = "INDEX_IRREGULAR"     // index has not constant step, STEP set to 0
+	WarnDepthMonotony    WarningCode = "DEPTH_NOT_MONOTONIC" // depth not monotonic
+	WarnDataEmptyLine    WarningCode = "DATA_EMPTY_LINE"     // empty line of data section
+	WarnDataColumnCount  WarningCode = "DATA_COLUMN_COUNT"   // number of values in line not equal to number of curves
+	WarnDataIndex        WarningCode = "DATA_INDEX"          // index value not numeric, line ignored
+	WarnDataMissing      WarningCode = "DATA_MISSING"        // value not present, set to NULL
+	WarnDataNotNumeric   WarningCode = "DATA_NOT_NUMERIC"    // value not numeric, set to NULL
+	WarnWrapRecordLength WarningCode = "WRAP_RECORD_LENGTH"  // wrapped record contains wrong number of values
+	WarnGroupDefinition  WarningCode = "GROUP_NO_DEFINITION" // data of las 3.0 group without definition
+	WarnDataStop         WarningCode = "DATA_STOP"           // STOP not equal to last depth
+	WarnDataStep         WarningCode = "DATA_STEP"           // STEP not match step of data
+	WarnDataColumns      WarningCode = "DATA_COLUMNS"        // lines with wrong number of columns found in data section
+	WarnDataMonotony     WarningCode = "DATA_NOT_MONOTONIC"  // depth not strictly monotonic
+	WarnDataNull         WarningCode = "DATA_NULL"           // data contain null value other than NULL
+	WarnSectionName      WarningCode = "SECTION_NO_NAME"     // title of section '~' without name, read as section ~O
+	WarnOutsideSection   WarningCode = "OUTSIDE_SECTION"     // line before first section ignored
+)
+
+// warningSeverity - severity of warning by code, not listed codes has SeverityWarning
+var warningSeverity = map[WarningCode]Severity{
+	WarnLimit:         SeverityInfo,
+	WarnDataEmptyLine: SeverityInfo,
+	WarnCurveSecEmpty: SeverityError,
+}
+
 // TWarning - class to store warning
 type TWarning struct {
-	direct  int    // 0 - undefine (warningUNDEF), 1 - on read (directOnRead), 2 - on write (directOnWrite)
-	section int    // 0 - undefine (warningUNDEF), lasSecVertion, lasSecWellInfo, lasSecCurInfo, lasSecData
-	line    int    // number of line in source file
-	desc    string // description of warning
+	Direct   int         // 0 - undefine (warningUNDEF), 1 - on read (directOnRead), 2 - on write (directOnWrite)
+	Section  int         // 0 - undefine (warningUNDEF), lasSecVertion, lasSecWellInfo, lasSecCurInfo, lasSecData
+	Line     int         // number of line in source file, output functions write Line+1
+	Desc     string      // description of warning
+	Code     WarningCode // kind of warning: DATA_COLUMN_COUNT, STEP_ZERO ...
+	Severity Severity
+}
+
+// newWarning - create warning on read, severity determined by code
+func newWarning(code WarningCode, section, line int, format string, a ...interface{}) TWarning {
+	sev, ok := warningSeverity[code]
+	if !ok {
+		sev = SeverityWarning
+	}
+	return TWarning{directOnRead, section, line, fmt.Sprintf(format, a...), code, sev}
 }
 
 // Empty - return true if warning is empty
 func (w TWarning) Empty() bool {
-	return len(w.desc) == 0
+	return len(w.Desc) == 0
+}
+
+// SectionName - return name of section of warning: ~V, ~W, ~C, ~A, "" if section undefined
+func (w TWarning) SectionName() string {
+	switch w.Section {
+	case lasSecVersion:
+		return "~V"
+	case lasSecWellInfo:
+		return "~W"
+	case lasSecCurInfo:
+		return "~C"
+	case lasSecData:
+		return "~A"
+	}
+	return ""
 }
 
 //String - return string with warning
 func (w TWarning) String() string {
-	return fmt.Sprintf("line: %d,\t\"%s\"", w.line+1, w.desc)
+	return fmt.Sprintf("line: %d,\t\"%s\"", w.Line+1, w.Desc)
 }
 
 // ToCsvString - return string with warning
@@ -41,7 +145,21 @@ func (w *TWarning) ToCsvString(sep ...string) string {
 	case 1:
 		fieldSep = sep[0]
 	}
-	return fmt.Sprintf("%3d%s \"%s\"", w.line+1, fieldSep, w.desc)
+	return fmt.Sprintf("%3d%s \"%s\"", w.Line+1, fieldSep, w.Desc)
+}
+
+// jsonWarning - warning as written to json
+type jsonWarning struct {
+	Code     WarningCode `json:"code"`
+	Severity Severity    `json:"severity"`
+	Section  string      `json:"section"`
+	Line     int         `json:"line"`
+	Desc     string      `json:"desc"`
+}
+
+// MarshalJSON - warning written as object: {"code": "STEP_ZERO", "severity": "warning", "section": "~W", "line": 9, "desc": "..."}
+func (w TWarning) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonWarning{w.Code, w.Severity, w.SectionName(), w.Line + 1, w.Desc})
 }
 
 //TLasWarnings - class to store and manipulate warnings
@@ -141,3 +259,18 @@ func (w *TLasWarnings) SaveWarningToFile(oFile *os.File) int {
 	}
 	return w.Count()
 }
+
+// csvHeader - columns of csv written by MarshalCSV
+var csvHeader = []string{"code", "severity", "section", "line", "desc"}
+
+// MarshalCSV - return warnings in csv format with header: code,severity,section,line,desc
+func (w TLasWarnings) MarshalCSV() ([]byte, error) {
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	writer.Write(csvHeader)
+	for _, wrn := range w {
+		writer.Write([]string{string(wrn.Code), wrn.Severity.String(), wrn.SectionName(), strconv.Itoa(wrn.Line + 1), wrn.Desc})
+	}
+	writer.Flush()
+	return b.Bytes(), writer.Error()
+}
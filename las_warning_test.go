@@ -1,19 +1,21 @@
 package glasio
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestToCsvString(t *testing.T) {
-	w := TWarning{1, 1, 1, "first"}
+	w := TWarning{Direct: 1, Section: 1, Line: 1, Desc: "first"}
 	assert.Equal(t, "  2; \"first\"", w.ToCsvString())
 
-	w = TWarning{2, 2, 2, " второе сообщение "}
+	w = TWarning{Direct: 2, Section: 2, Line: 2, Desc: " второе сообщение "}
 	assert.Equal(t, "  3,\t \" второе сообщение \"", w.ToCsvString(",\t"))
 
-	w = TWarning{1, 1, 1, "first"}
+	w = TWarning{Direct: 1, Section: 1, Line: 1, Desc: "first"}
 	assert.Equal(t, "  2, \"first\"", w.ToCsvString(","))
 }
 
@@ -22,9 +24,50 @@ func TestLasWarningsToString(t *testing.T) {
 	assert.Equal(t, "", warnings.ToString(""))
 
 	warnings = TLasWarnings{
-		TWarning{1, 1, 1, "first"},
-		TWarning{2, 2, 2, "second"},
+		TWarning{Direct: 1, Section: 1, Line: 1, Desc: "first"},
+		TWarning{Direct: 2, Section: 2, Line: 2, Desc: "second"},
 	}
 	assert.Equal(t, " 0,   2, \"first\"# 1,   3, \"second\"#", warnings.ToString("#"))
 	assert.Equal(t, " 0,   2, \"first\"\n 1,   3, \"second\"\n", warnings.ToString("\n"))
 }
+
+func TestWarningCodes(t *testing.T) {
+	src := strings.Join([]string{
+		"~V", "VERS. 2.0 :", "WRAP. NO :", "~W", "STRT.M 1.0 :", "STOP.M 3.0 :", "STEP.M 0 :", "NULL. -999.25 :", "WELL. W1 :",
+		"~C", "DEPT.M :", "A. :", "~A", "1.0 10", "2.0 x", "3.0 30 40"}, "\n")
+	las := NewLas()
+	las.Load(strings.NewReader(src))
+	codes := make([]WarningCode, 0, len(las.Warnings))
+	for _, w := range las.Warnings {
+		codes = append(codes, w.Code)
+		assert.Equal(t, SeverityWarning, w.Severity, w.Desc)
+	}
+	assert.ElementsMatch(t, []WarningCode{WarnStepZero, WarnDataNotNumeric, WarnDataColumnCount, WarnDataColumns}, codes)
+	for _, w := range las.Warnings {
+		if w.Code == WarnDataColumnCount {
+			assert.Equal(t, "~A", w.SectionName())
+			assert.Equal(t, 15, w.Line)
+		}
+	}
+	assert.Equal(t, SeverityError, newWarning(WarnCurveSecEmpty, lasSecCurInfo, 0, "").Severity)
+	assert.Equal(t, "info", SeverityInfo.String())
+
+	// json: array of objects with code, severity, section, line, desc
+	w := TLasWarnings{newWarning(WarnStepZero, lasSecWellInfo, 6, "STEP parameter equal 0")}
+	b, err := json.Marshal(w)
+	assert.Nil(t, err)
+	assert.Equal(t, `[{"code":"STEP_ZERO","severity":"warning","section":"~W","line":7,"desc":"STEP parameter equal 0"}]`, string(b))
+	b, err = json.Marshal(las.Warnings)
+	assert.Nil(t, err)
+	var res []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b, &res))
+	assert.Equal(t, len(las.Warnings), len(res))
+
+	// csv with header, text with separator quoted
+	w = append(w, newWarning(WarnDataNotNumeric, lasSecData, 14, "error convert string: '%s' to number, set to NULL", "1,5"))
+	b, err = w.MarshalCSV()
+	assert.Nil(t, err)
+	assert.Equal(t, "code,severity,section,line,desc\n"+
+		"STEP_ZERO,warning,~W,7,STEP parameter equal 0\n"+
+		"DATA_NOT_NUMERIC,warning,~A,15,\"error convert string: '1,5' to number, set to NULL\"\n", string(b))
+}
@@ -0,0 +1,156 @@
+// (c) softland 2020
+// softlandia@gmail.com
+// typed information about well from section ~W
+
+package glasio
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Measure - numeric value of parameter with unit
+type Measure struct {
+	Value float64
+	Unit  string
+	Valid bool // false if parameter not exist or value is not number
+}
+
+// String - return value of measure as string, "" if value not valid
+func (m Measure) String() string {
+	if !m.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(m.Value, 'f', -1, 64)
+}
+
+// parseMeasure - convert parameter to measure
+// unit may be written after value: "XWELL.  1234.5 M : X"
+func parseMeasure(p HeaderParam) Measure {
+	fields := strings.Fields(p.Val)
+	if len(fields) == 0 {
+		return Measure{Unit: p.Unit}
+	}
+	v, err := strconv.ParseFloat(strings.Replace(fields[0], ",", ".", 1), 64)
+	if err != nil {
+		return Measure{Unit: p.Unit}
+	}
+	m := Measure{v, p.Unit, true}
+	if (len(m.Unit) == 0) && (len(fields) > 1) {
+		m.Unit = fields[1]
+	}
+	return m
+}
+
+// WellInfo - information about well from section ~W, filled on load
+// changed fields written to section ~W on save, WelSec can be used to read and change other parameters
+type WellInfo struct {
+	Comp    string    // COMP: oil company
+	Field   string    // FLD: field
+	Loc     string    // LOC: location
+	Country string    // CTRY: country
+	Srvc    string    // SRVC: service company
+	Date    time.Time // DATE: date of logging, zero if parameter not exist or date not recognized
+	UWI     string    // UWI: unique well identifier
+	API     string    // API: API number
+	X       Measure   // XWELL, XCOORD: X coordinate of well head
+	Y       Measure   // YWELL, YCOORD: Y coordinate of well head
+	Rkb     Measure   // RKB, EKB, KB: elevation of kelly bushing
+}
+
+// wellInfoNames - names of parameters of WellInfo, first name used if parameter not exist in file
+var wellInfoNames = map[string][]string{
+	"COMP":  {"COMP"},
+	"FLD":   {"FLD"},
+	"LOC":   {"LOC"},
+	"CTRY":  {"CTRY"},
+	"SRVC":  {"SRVC"},
+	"DATE":  {"DATE"},
+	"UWI":   {"UWI"},
+	"API":   {"API"},
+	"XWELL": {"XWELL", "XCOORD", "X"},
+	"YWELL": {"YWELL", "YCOORD", "Y"},
+	"RKB":   {"RKB", "EKB", "KB"},
+}
+
+// wellParam - return parameter of WellInfo by canonical name, parameter searched by all names of it
+func (las *Las) wellParam(name string) (HeaderParam, bool) {
+	for _, n := range wellInfoNames[name] {
+		if p, ok := las.WelSec.Get(n); ok {
+			return p, true
+		}
+	}
+	return HeaderParam{Name: name}, false
+}
+
+// readWellInfo - fill WellInfo from section ~W, called after load of header
+func (las *Las) readWellInfo() {
+	str := func(name string) string {
+		p, _ := las.wellParam(name)
+		return p.Val
+	}
+	measure := func(name string) Measure {
+		p, _ := las.wellParam(name)
+		return parseMeasure(p)
+	}
+	w := WellInfo{Comp: str("COMP"), Field: str("FLD"), Loc: str("LOC"), Country: str("CTRY"), Srvc: str("SRVC"),
+		UWI: str("UWI"), API: str("API"), X: measure("XWELL"), Y: measure("YWELL"), Rkb: measure("RKB")}
+	w.Date, _ = parseDate(str("DATE"))
+	las.WellInfo = w
+	las.wellInfo = w
+}
+
+// storeWellInfo - write changed fields of WellInfo to section ~W, called before save
+func (las *Las) storeWellInfo() {
+	w, old := las.WellInfo, las.wellInfo
+	setStr := func(name, val, oldVal string) {
+		if val != oldVal {
+			p, _ := las.wellParam(name)
+			p.Val = val
+			las.WelSec.Set(p)
+		}
+	}
+	setMeasure := func(name string, m, oldM Measure) {
+		if m != oldM {
+			p, _ := las.wellParam(name)
+			p.Val, p.Unit = m.String(), m.Unit
+			las.WelSec.Set(p)
+		}
+	}
+	setStr("COMP", w.Comp, old.Comp)
+	setStr("FLD", w.Field, old.Field)
+	setStr("LOC", w.Loc, old.Loc)
+	setStr("CTRY", w.Country, old.Country)
+	setStr("SRVC", w.Srvc, old.Srvc)
+	setStr("UWI", w.UWI, old.UWI)
+	setStr("API", w.API, old.API)
+	if !w.Date.Equal(old.Date) {
+		p, _ := las.wellParam("DATE")
+		p.Val = ""
+		if !w.Date.IsZero() {
+			p.Val = strings.ToUpper(w.Date.Format("02-Jan-2006"))
+		}
+		las.WelSec.Set(p)
+	}
+	setMeasure("XWELL", w.X, old.X)
+	setMeasure("YWELL", w.Y, old.Y)
+	setMeasure("RKB", w.Rkb, old.Rkb)
+	las.wellInfo = w
+}
+
+// wellTemplates - templates to save parameters of WellInfo and standard descriptions
+// templates of XWELL, YWELL, RKB contain unit
+var wellTemplates = map[string][2]string{
+	"COMP":  {_LasOilComp, "OIL COMPANY"},
+	"FLD":   {_LasField, "FIELD"},
+	"LOC":   {_LasLoc, "LOCATION"},
+	"CTRY":  {_LasCountry, "COUNTRY"},
+	"SRVC":  {_LasServiceComp, "SERVICE COMPANY"},
+	"DATE":  {_LasDate, "DATE"},
+	"UWI":   {_LasUwi, "UNIVERSAL WELL INDEX"},
+	"API":   {_LasAPI, "API NUMBER"},
+	"XWELL": {_LasXcoord, "WELL HEAD X COORDINATE"},
+	"YWELL": {_LasYcoord, "WELL HEAD Y COORDINATE"},
+	"RKB":   {_LasRkb, "KB OR GL"},
+}
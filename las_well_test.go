@@ -0,0 +1,159 @@
+//(c) softland 2020
+//softlandia@gmail.com
+package glasio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tParseMeasure struct {
+	p HeaderParam
+	m Measure
+}
+
+var dParseMeasure = []tParseMeasure{
+	{HeaderParam{Val: "1234.5", Unit: "M"}, Measure{1234.5, "M", true}},
+	{HeaderParam{Val: "1234,5 FT"}, Measure{1234.5, "FT", true}},
+	{HeaderParam{Val: "-12", Unit: "M"}, Measure{-12, "M", true}},
+	{HeaderParam{Val: "N/A", Unit: "M"}, Measure{0, "M", false}},
+	{HeaderParam{}, Measure{}},
+}
+
+func TestParseMeasure(t *testing.T) {
+	for _, tmp := range dParseMeasure {
+		assert.Equal(t, tmp.m, parseMeasure(tmp.p), tmp.p.Val)
+	}
+}
+
+func TestWellInfo(t *testing.T) {
+	las := NewLas()
+	_, err := las.Open("test_files/sample_wellinfo.las")
+	assert.Nil(t, err)
+	w := las.WellInfo
+	assert.Equal(t, "ANY OIL COMPANY INC.", w.Comp)
+	assert.Equal(t, "WILDCAT", w.Field)
+	assert.Equal(t, "12-34-12-34W5M", w.Loc)
+	assert.Equal(t, "CANADA", w.Country)
+	assert.Equal(t, "ANY LOGGING COMPANY", w.Srvc)
+	assert.Equal(t, time.Date(2008, 11, 5, 0, 0, 0, 0, time.UTC), w.Date)
+	assert.Equal(t, "0512337066000", w.UWI)
+	assert.Equal(t, "0512337066", w.API)
+	assert.Equal(t, Measure{6543210.55, "M", true}, w.X)
+	assert.Equal(t, Measure{512345.5, "M", true}, w.Y)
+	assert.Equal(t, Measure{1234.5, "FT", true}, w.Rkb)
+
+	// unchanged parameters written through templates, values and descriptions kept
+	b, _ := las.SaveToBuf(false)
+	s := string(b)
+	assert.Contains(t, s, "\n COMP.   ANY OIL COMPANY INC.                       :COMPANY\n")
+	assert.Contains(t, s, "\n DATE.   05-Nov-08                                  :LOG DATE\n")
+	assert.Contains(t, s, "\n XWELL.M   6543210.55                                  :X COORDINATE\n")
+	las2 := NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, las.WellInfo, las2.WellInfo)
+
+	// changed fields written to section ~W
+	las.WellInfo.Comp = "NEW COMPANY"
+	las.WellInfo.Date = time.Date(1988, 12, 25, 0, 0, 0, 0, time.UTC)
+	las.WellInfo.X.Value = 100.5
+	las.WellInfo.Rkb = Measure{376.3, "M", true}
+	las.WellInfo.Country = ""
+	b, _ = las.SaveToBuf(false)
+	s = string(b)
+	assert.Contains(t, s, "\n DATE.   25-DEC-1988                                :LOG DATE\n")
+	assert.Equal(t, "NEW COMPANY", las.WelSec.Value("COMP"))
+	assert.Equal(t, "100.5", las.WelSec.Value("XWELL"))
+	p, _ := las.WelSec.Get("EKB")
+	assert.Equal(t, "376.3", p.Val)
+	assert.Equal(t, "M", p.Unit)
+	las2 = NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, las.WellInfo, las2.WellInfo)
+
+	// parameters not exist in file added to section ~W with standard names
+	las = NewLas()
+	las.Open("test_files/sample_time.las")
+	assert.False(t, las.WellInfo.X.Valid)
+	assert.True(t, las.WellInfo.Date.IsZero())
+	las.WellInfo.X = Measure{10, "M", true}
+	las.WellInfo.Date = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	b, _ = las.SaveToBuf(false)
+	s = string(b)
+	assert.Contains(t, s, "\n XWELL.M           10                                  :WELL HEAD X COORDINATE\n")
+	assert.Contains(t, s, "\n DATE.   01-JUN-2020")
+	assert.True(t, strings.Index(s, " WELL.") < strings.Index(s, " XWELL."))
+}
+
+type tWellParam12 struct {
+	name string
+	val  string
+	desc string
+}
+
+var dWellParam12 = []tWellParam12{
+	{"COMP", "ANY OIL COMPANY LTD.", "COMPANY"},
+	{"WELL", "ANY ET AL OIL WELL #12", "WELL"},
+	{"FLD", "EDAM", "FIELD"},
+	{"LOC", "A9-16-49-20W3M", "LOCATION"},
+	{"PROV", "SASKATCHEWAN", "PROVINCE"},
+	{"SRVC", "ANY LOGGING COMPANY LTD.", "SERVICE COMPANY"},
+	{"DATE", "25-DEC-1988", "LOG DATE"},
+	{"UWI", "100091604920W300", "UNIQUE WELL ID"},
+}
+
+func TestWellSection12(t *testing.T) {
+	las := NewLas()
+	_, err := las.Open("data/1.2/sample_curve_api.las")
+	assert.Nil(t, err)
+	check := func(las *Las, msg string) {
+		for _, tmp := range dWellParam12 {
+			p, ok := las.WelSec.Get(tmp.name)
+			assert.True(t, ok, msg+" "+tmp.name)
+			assert.Equal(t, tmp.val, p.Val, msg+" "+tmp.name)
+			assert.Equal(t, tmp.desc, p.Desc, msg+" "+tmp.name)
+		}
+		assert.Equal(t, 1670.0, las.STRT(), msg)
+		assert.Equal(t, -999.25, las.NULL(), msg)
+		assert.Equal(t, "ANY OIL COMPANY LTD.", las.WellInfo.Comp, msg)
+		assert.Equal(t, time.Date(1988, 12, 25, 0, 0, 0, 0, time.UTC), las.WellInfo.Date, msg)
+	}
+	check(las, "1.2")
+	// save as las 2.0: value before colon
+	b, _ := las.SaveToBuf(false)
+	assert.Contains(t, string(b), "\n COMP.   ANY OIL COMPANY LTD.                       :COMPANY\n")
+	las2 := NewLas()
+	las2.Load(bytes.NewReader(b))
+	check(las2, "2.0")
+	// save as las 1.2: layout of source kept
+	assert.Nil(t, las.SetSaveVersion(1.2))
+	b, _ = las.SaveToBuf(false)
+	assert.Contains(t, string(b), "VERS.                          1.2 :")
+	assert.Contains(t, string(b), "\n COMP .           COMPANY                        : ANY OIL COMPANY LTD.\n")
+	assert.Contains(t, string(b), "\n STRT.M     1670 ")
+	las2 = NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, 1.2, las2.VERS())
+	check(las2, "save 1.2")
+	// parameter of las 2.0 saved as las 1.2
+	las2.WelSec.Set(HeaderParam{Name: "CTRY", Val: "CANADA"})
+	b, _ = las2.SaveToBuf(false)
+	las2 = NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, "CANADA", las2.WelSec.Value("CTRY"))
+
+	// numeric value after colon
+	las = NewLas()
+	las.Open("data/UWI_API_leading_zero.las")
+	assert.Equal(t, "05123370660000", las.WellInfo.UWI)
+	assert.Equal(t, "UNIQUE WELL ID", las.WelSec.params["UWI"].Desc)
+	las.SetSaveVersion(1.2)
+	b, _ = las.SaveToBuf(false)
+	las2 = NewLas()
+	las2.Load(bytes.NewReader(b))
+	assert.Equal(t, "05123370660000", las2.WellInfo.UWI)
+}